@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// downloadCmd is a convenience wrapper around `lobster <query> --download`:
+// it runs the exact same search/select/resolve flow as the root command,
+// but always downloads instead of playing, falling back to cfg.DownloadDir
+// when --download wasn't given a path.
+var downloadCmd = &cobra.Command{
+	Use:   "download [query]",
+	Short: "Search and download instead of playing",
+	Args:  cobra.ArbitraryArgs,
+	RunE:  downloadRun,
+}
+
+func downloadRun(cmd *cobra.Command, args []string) error {
+	if flagDownload == "" {
+		dir, err := cfg.ExpandDownloadDir()
+		if err != nil {
+			return fmt.Errorf("resolving download dir: %w", err)
+		}
+		flagDownload = dir
+	}
+	return searchRun(cmd, args)
+}
+
+func init() {
+	rootCmd.AddCommand(downloadCmd)
+}
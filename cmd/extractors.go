@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"lobster/internal/extract"
+)
+
+var extractorsCmd = &cobra.Command{
+	Use:   "extractors",
+	Short: "List registered embed-host extractors",
+	Args:  cobra.NoArgs,
+	RunE:  extractorsRun,
+}
+
+func extractorsRun(cmd *cobra.Command, args []string) error {
+	names := extract.Extractors()
+	if len(names) == 0 {
+		fmt.Println("No extractors registered.")
+		return nil
+	}
+
+	for _, name := range names {
+		fmt.Printf("%s\tregistered\n", name)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(extractorsCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"lobster/internal/media"
+	"lobster/internal/quality"
+)
+
+// filterResults drops results whose parsed release Tags (see media.SearchResult.Tags)
+// fall below --min-quality, are rejected by --no-cam, or match a --exclude-source
+// entry, then sorts the survivors best-first by quality.Rank (which also applies
+// --prefer-codec as a tiebreaker). Most streaming-site titles carry no release
+// tags at all (Tags is the zero value), and those are always kept and left in
+// their original relative order — there's nothing to judge or rank them against.
+func filterResults(results []media.SearchResult) []media.SearchResult {
+	prefs := quality.Current()
+	if flagMinQuality == "" && flagExcludeSource == "" && !flagNoCam && flagPreferCodec == "" {
+		return results
+	}
+
+	excluded := map[string]bool{}
+	for _, s := range strings.Split(flagExcludeSource, ",") {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			excluded[s] = true
+		}
+	}
+
+	filtered := make([]media.SearchResult, 0, len(results))
+	for _, r := range results {
+		if r.Tags.LowQuality && (flagNoCam || excluded["CAM"] || excluded["TS"]) {
+			continue
+		}
+		if prefs.MinResolution != "" && r.Tags.Resolution != "" && resolutionHeight(r.Tags.Resolution) < resolutionHeight(prefs.MinResolution) {
+			continue
+		}
+		if r.Tags.Source != "" && excluded[strings.ToUpper(r.Tags.Source)] {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return quality.Rank(filtered[i].Tags, prefs) > quality.Rank(filtered[j].Tags, prefs)
+	})
+
+	return filtered
+}
+
+// resolutionHeight parses a resolution tag like "1080p" or a bare "1080"
+// into its numeric pixel height, or 0 if it doesn't look like one.
+func resolutionHeight(s string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(s), "p"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"lobster/internal/media"
+	"lobster/internal/provider"
+)
+
+// loadMoreLabel is the sentinel row appended to an interactive selection
+// list when more pages are available. Picking it fetches the next page
+// and re-displays the list instead of ending the selection.
+const loadMoreLabel = "Load more…"
+
+// selectPaged shows results (the first page already fetched by the
+// caller) for interactive selection, honoring --page/--all-pages:
+//
+//   - fetchPage is nil when the provider doesn't implement provider.Pager,
+//     or lastPage <= 1: no paging controls, plain selection over results.
+//   - --page N was given: the caller already fetched exactly that page;
+//     show it as-is, no "Load more" row.
+//   - --all-pages was given: eagerly fetch every remaining page first,
+//     then show the combined list with no "Load more" row.
+//   - otherwise: show one page at a time with a trailing "Load more" row
+//     that fetches and appends the next page when picked.
+func selectPaged(prompt string, results []media.SearchResult, lastPage int, fetchPage func(page int) ([]media.SearchResult, int, error)) (media.SearchResult, error) {
+	if fetchPage == nil || lastPage <= 1 || flagPage > 0 {
+		idx, err := selector.Select(prompt, formatTitles(results))
+		if err != nil {
+			return media.SearchResult{}, err
+		}
+		return results[idx], nil
+	}
+
+	if flagAllPages {
+		for page := 2; page <= lastPage; page++ {
+			more, _, err := fetchPage(page)
+			if err != nil {
+				return media.SearchResult{}, fmt.Errorf("loading page %d: %w", page, err)
+			}
+			results = append(results, more...)
+		}
+		idx, err := selector.Select(prompt, formatTitles(results))
+		if err != nil {
+			return media.SearchResult{}, err
+		}
+		return results[idx], nil
+	}
+
+	page := 1
+	for {
+		items := formatTitles(results)
+		hasMore := page < lastPage
+		if hasMore {
+			items = append(items, loadMoreLabel)
+		}
+
+		idx, err := selector.Select(prompt, items)
+		if err != nil {
+			return media.SearchResult{}, err
+		}
+
+		if hasMore && idx == len(items)-1 {
+			page++
+			more, lp, err := fetchPage(page)
+			if err != nil {
+				return media.SearchResult{}, fmt.Errorf("loading page %d: %w", page, err)
+			}
+			results = append(results, more...)
+			lastPage = lp
+			continue
+		}
+
+		return results[idx], nil
+	}
+}
+
+// formatTitles renders results as display strings for selector.Select.
+func formatTitles(results []media.SearchResult) []string {
+	items := make([]string, len(results))
+	for i, r := range results {
+		items[i] = provider.FormatDisplayTitle(r)
+	}
+	return items
+}
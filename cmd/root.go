@@ -2,13 +2,23 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"lobster/internal/cache"
 	"lobster/internal/config"
+	"lobster/internal/download"
+	"lobster/internal/extract"
+	"lobster/internal/provider"
+	"lobster/internal/provider/torznab"
+	"lobster/internal/quality"
+	"lobster/internal/ui"
 )
 
 // Version is set at build time via ldflags.
@@ -16,20 +26,40 @@ var Version = "dev"
 
 // Global flags
 var (
-	flagDownload string
-	flagLanguage string
-	flagNoSubs   bool
-	flagProvider string
-	flagQuality  string
-	flagPlayer   string
-	flagContinue bool
-	flagJSON     bool
-	flagDebug    bool
+	flagDownload      string
+	flagLanguage      string
+	flagNoSubs        bool
+	flagProvider      string
+	flagQuality       string
+	flagPlayer        string
+	flagContinue      bool
+	flagJSON          bool
+	flagDebug         bool
+	flagClearCache    bool
+	flagSource        string
+	flagSite          string
+	flagConcurrency   int
+	flagResume        bool
+	flagMinQuality    string
+	flagExcludeSource string
+	flagNoCache       bool
+	flagPage          int
+	flagAllPages      bool
+	flagNFO           bool
+	flagLayout        string
+	flagPreferCodec   string
+	flagNoCam         bool
+	flagSubsLanguages string
+	flagDownloader    string
 )
 
 // cfg holds the loaded configuration (merged: defaults < config file < flags).
 var cfg *config.Config
 
+// selector is the interactive selection backend (fzf/plain/menu), chosen
+// in loadConfig from cfg.UI.Backend or auto-detected via ui.Default.
+var selector ui.Selector
+
 var rootCmd = &cobra.Command{
 	Use:   "lobster [query]",
 	Short: "Stream movies and TV shows from the terminal",
@@ -47,6 +77,13 @@ func Execute() {
 	}
 }
 
+// rootCtx returns a context cancelled on the first Ctrl-C (interrupt), so a
+// slow embed fetch or provider page can't hang the process. A second
+// interrupt falls through to the default (immediate-exit) behavior.
+func rootCtx() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&flagDownload, "download", "d", "", "Download to path instead of playing")
 	rootCmd.PersistentFlags().StringVarP(&flagLanguage, "language", "l", "", "Subtitle language (default: english)")
@@ -57,15 +94,41 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&flagContinue, "continue", "c", false, "Auto-resume from history")
 	rootCmd.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "Output stream metadata as JSON")
 	rootCmd.PersistentFlags().BoolVarP(&flagDebug, "debug", "x", false, "Debug logging to stderr")
+	rootCmd.PersistentFlags().BoolVar(&flagClearCache, "clear-cache", false, "Clear the on-disk cache and exit")
+	rootCmd.PersistentFlags().StringVar(&flagSource, "source", "embed", "Content source: embed | torrent")
+	rootCmd.PersistentFlags().StringVar(&flagSite, "site", "", "Content provider site, e.g. flixhq (default: flixhq)")
+	rootCmd.PersistentFlags().IntVar(&flagConcurrency, "concurrency", download.DefaultConcurrency, "Parallel workers for segmented downloads (0: single-stream ffmpeg)")
+	rootCmd.PersistentFlags().BoolVar(&flagResume, "resume", false, "Resume an interrupted download from its .part.json sidecar")
+	rootCmd.PersistentFlags().StringVar(&flagMinQuality, "min-quality", "", "Hide results below this resolution, e.g. 720 | 1080 | 2160")
+	rootCmd.PersistentFlags().StringVar(&flagExcludeSource, "exclude-source", "", "Hide results matching these comma-separated release tags, e.g. CAM,TS")
+	rootCmd.PersistentFlags().BoolVar(&flagNoCache, "no-cache", false, "Bypass the on-disk provider response cache")
+	rootCmd.PersistentFlags().IntVar(&flagPage, "page", 0, "Fetch a specific results page instead of browsing interactively")
+	rootCmd.PersistentFlags().BoolVar(&flagAllPages, "all-pages", false, "Eagerly fetch every results page instead of browsing interactively")
+	rootCmd.PersistentFlags().BoolVar(&flagNFO, "nfo", true, "Write a Kodi/Jellyfin .nfo and poster/fanart alongside --download output")
+	rootCmd.PersistentFlags().StringVar(&flagLayout, "layout", "", "Plex/Kodi-style library layout for --download output: movie | tv | anime (default: flat, see naming_template)")
+	rootCmd.PersistentFlags().StringVar(&flagPreferCodec, "prefer-codec", "", "Break quality ties in favor of this codec, e.g. x265 | x264")
+	rootCmd.PersistentFlags().BoolVar(&flagNoCam, "no-cam", false, "Reject cam/telesync releases outright instead of just deprioritizing them")
+	rootCmd.PersistentFlags().StringVar(&flagSubsLanguages, "subs-languages", "", "Comma-separated languages to mux as subtitle tracks into --download output, e.g. english,spanish,french (default: the single --language/subs_language track)")
+	rootCmd.PersistentFlags().StringVar(&flagDownloader, "downloader", "", "Download backend: ffmpeg (single ffmpeg invocation) | native (concurrent segment fetcher, --concurrency workers); default: native unless --concurrency=0")
 
 	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(trendingCmd)
 	rootCmd.AddCommand(recentCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(traktCmd)
+	rootCmd.AddCommand(cacheCmd)
 }
 
 // loadConfig loads and merges configuration: defaults < config file < CLI flags.
 func loadConfig(cmd *cobra.Command, args []string) error {
+	if flagClearCache {
+		if err := cache.ClearAll(); err != nil {
+			return fmt.Errorf("clearing cache: %w", err)
+		}
+		fmt.Println("Cache cleared.")
+		os.Exit(0)
+	}
+
 	var err error
 	cfg, err = config.Load()
 	if err != nil {
@@ -79,6 +142,9 @@ func loadConfig(cmd *cobra.Command, args []string) error {
 	if flagProvider != "" {
 		cfg.Provider = flagProvider
 	}
+	if flagSite != "" {
+		cfg.Site = flagSite
+	}
 	if flagQuality != "" {
 		cfg.Quality = flagQuality
 	}
@@ -88,12 +154,80 @@ func loadConfig(cmd *cobra.Command, args []string) error {
 	if flagDebug {
 		cfg.Debug = true
 	}
+	if !cmd.Flags().Changed("concurrency") && cfg.DownloadConcurrency != 0 {
+		flagConcurrency = cfg.DownloadConcurrency
+	}
+	if flagLayout != "" {
+		cfg.DownloadLayoutFormat = flagLayout
+	}
+
+	// --downloader is a friendlier front-end over --concurrency's
+	// ffmpeg-vs-native switch (Options.Concurrency <= 0 means ffmpeg-only,
+	// see download.Download). "ffmpeg" always forces the ffmpeg path, even
+	// over an explicit --concurrency; "native" only restores the default
+	// worker count when the user didn't pin an explicit --concurrency value.
+	if flagDownloader != "" {
+		switch strings.ToLower(flagDownloader) {
+		case "ffmpeg":
+			flagConcurrency = 0
+		case "native":
+			if !cmd.Flags().Changed("concurrency") && flagConcurrency <= 0 {
+				flagConcurrency = download.DefaultConcurrency
+			}
+		default:
+			return fmt.Errorf("unsupported downloader %q (valid: ffmpeg, native)", flagDownloader)
+		}
+	}
 
 	// Re-validate after flag overrides
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	// The megacloud extractor registers itself at init() time, before
+	// config is loaded, so it starts out on DefaultNetConfig. Rebuild it
+	// now that the real [network] settings are known.
+	extract.Default.Replace("megacloud", extract.NewMegaCloud(cfg.Network))
+
+	if flagNoCache {
+		provider.SetCachingEnabled(false)
+	}
+
+	quality.SetPrefs(quality.Prefs{
+		MinResolution: flagMinQuality,
+		PreferCodec:   flagPreferCodec,
+		NoCam:         flagNoCam,
+	})
+
+	hooksCfg, err := download.LoadHooksConfig()
+	if err != nil {
+		return fmt.Errorf("loading hooks config: %w", err)
+	}
+	download.RegisterConfiguredHooks(hooksCfg)
+
+	// The torznab content source reads its indexer URL/key from [torznab]
+	// rather than Base/cfg.Provider: cfg.Base is repurposed as the
+	// indexer's root URL so provider.Registry.New's single base-URL
+	// Factory signature can still construct it.
+	if cfg.Site == "torznab" {
+		torznab.SetAPIKey(cfg.Torznab.APIKey)
+		if cfg.Torznab.IndexerURL != "" {
+			cfg.Base = cfg.Torznab.IndexerURL
+		}
+	}
+
+	if cfg.UI.Backend != "" {
+		s, err := ui.New(cfg.UI.Backend)
+		if err != nil {
+			return err
+		}
+		selector = s
+	} else {
+		selector = ui.Default()
+	}
+
+	setupTraktSync()
+
 	if cfg.Debug {
 		log.SetOutput(os.Stderr)
 		log.SetPrefix("[lobster] ")
@@ -1,271 +1,526 @@
-package cmd
-
-import (
-	"encoding/json"
-	"fmt"
-	"os"
-	"strings"
-
-	"github.com/spf13/cobra"
-
-	"lobster/internal/download"
-	"lobster/internal/extract"
-	"lobster/internal/history"
-	"lobster/internal/media"
-	"lobster/internal/player"
-	"lobster/internal/provider"
-	"lobster/internal/subtitle"
-	"lobster/internal/ui"
-)
-
-// searchRun is the default command: lobster <query>
-func searchRun(cmd *cobra.Command, args []string) error {
-	query := strings.Join(args, " ")
-
-	if query == "" {
-		// Prompt for query via fzf
-		var err error
-		query, err = ui.Input("Search")
-		if err != nil {
-			return fmt.Errorf("no search query provided")
-		}
-	}
-
-	debugf("searching for: %s", query)
-
-	p := provider.NewFlixHQ(cfg.Base)
-	return playFlow(p, query)
-}
-
-// playFlow handles the full search -> select -> play flow.
-func playFlow(p provider.Provider, query string) error {
-	// Search
-	results, err := p.Search(query)
-	if err != nil {
-		return fmt.Errorf("search failed: %w", err)
-	}
-
-	// Select content
-	items := make([]string, len(results))
-	for i, r := range results {
-		items[i] = provider.FormatDisplayTitle(r)
-	}
-
-	idx, err := ui.Select("Select", items)
-	if err != nil {
-		return err
-	}
-
-	selected := results[idx]
-	debugf("selected: %s (ID: %s, type: %s)", selected.Title, selected.ID, selected.Type)
-
-	return resolveAndPlay(p, selected, 0, 0)
-}
-
-// resolveAndPlay handles season/episode selection for TV and then plays.
-func resolveAndPlay(p provider.Provider, selected media.SearchResult, season, episode int) error {
-	episodeID := ""
-	title := selected.Title
-
-	if selected.Type == media.TV {
-		// Get seasons
-		seasons, err := p.GetSeasons(selected.ID)
-		if err != nil {
-			return fmt.Errorf("getting seasons: %w", err)
-		}
-
-		if len(seasons) == 0 {
-			return fmt.Errorf("no seasons found")
-		}
-
-		// Select season (or use provided)
-		seasonIdx := 0
-		if season > 0 {
-			for i, s := range seasons {
-				if s.Number == season {
-					seasonIdx = i
-					break
-				}
-			}
-		} else {
-			seasonItems := make([]string, len(seasons))
-			for i, s := range seasons {
-				seasonItems[i] = fmt.Sprintf("Season %d", s.Number)
-			}
-			seasonIdx, err = ui.Select("Season", seasonItems)
-			if err != nil {
-				return err
-			}
-		}
-
-		selectedSeason := seasons[seasonIdx]
-		debugf("season: %d (ID: %s)", selectedSeason.Number, selectedSeason.ID)
-
-		// Get episodes
-		episodes, err := p.GetEpisodes(selected.ID, selectedSeason.ID)
-		if err != nil {
-			return fmt.Errorf("getting episodes: %w", err)
-		}
-
-		if len(episodes) == 0 {
-			return fmt.Errorf("no episodes found")
-		}
-
-		// Select episode (or use provided)
-		episodeIdx := 0
-		if episode > 0 {
-			for i, ep := range episodes {
-				if ep.Number == episode {
-					episodeIdx = i
-					break
-				}
-			}
-		} else {
-			episodeItems := make([]string, len(episodes))
-			for i, ep := range episodes {
-				if ep.Title != "" {
-					episodeItems[i] = fmt.Sprintf("Episode %d: %s", ep.Number, ep.Title)
-				} else {
-					episodeItems[i] = fmt.Sprintf("Episode %d", ep.Number)
-				}
-			}
-			episodeIdx, err = ui.Select("Episode", episodeItems)
-			if err != nil {
-				return err
-			}
-		}
-
-		selectedEpisode := episodes[episodeIdx]
-		episodeID = selectedEpisode.ID
-		title = fmt.Sprintf("%s S%02dE%02d", selected.Title, selectedSeason.Number, selectedEpisode.Number)
-		season = selectedSeason.Number
-		episode = selectedEpisode.Number
-
-		debugf("episode: %d (ID: %s)", selectedEpisode.Number, episodeID)
-	}
-
-	// Get servers
-	servers, err := p.GetServers(selected.ID, episodeID)
-	if err != nil {
-		return fmt.Errorf("getting servers: %w", err)
-	}
-
-	if len(servers) == 0 {
-		return fmt.Errorf("no servers found")
-	}
-
-	// Find preferred server
-	serverIdx := 0
-	for i, s := range servers {
-		if strings.EqualFold(s.Name, cfg.Provider) {
-			serverIdx = i
-			break
-		}
-	}
-	debugf("using server: %s (ID: %s)", servers[serverIdx].Name, servers[serverIdx].ID)
-
-	// Get embed URL
-	embedURL, err := p.GetEmbedURL(servers[serverIdx].ID)
-	if err != nil {
-		return fmt.Errorf("getting embed URL: %w", err)
-	}
-	debugf("embed URL: %s", embedURL)
-
-	// Extract stream from embed URL
-	ext := extract.New()
-	stream, err := ext.Extract(embedURL, cfg.Quality)
-	if err != nil {
-		return fmt.Errorf("decrypting stream: %w", err)
-	}
-	debugf("stream URL: %s", stream.URL)
-
-	// JSON output mode
-	if flagJSON {
-		out := map[string]interface{}{
-			"title":     title,
-			"url":       stream.URL,
-			"quality":   stream.Quality,
-			"subtitles": stream.Subtitles,
-		}
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(out)
-	}
-
-	// Handle subtitles
-	var subFile string
-	if !flagNoSubs && len(stream.Subtitles) > 0 {
-		best := subtitle.BestMatch(stream.Subtitles, cfg.SubsLanguage)
-		if best != nil {
-			tmpDir, err := subtitle.NewTempDir()
-			if err == nil {
-				defer tmpDir.Cleanup()
-				subFile, err = tmpDir.Download(*best)
-				if err != nil {
-					debugf("subtitle download failed: %v", err)
-					subFile = "" // Continue without subs
-				} else {
-					debugf("subtitle file: %s", subFile)
-				}
-			}
-		}
-	}
-
-	// Download mode
-	if flagDownload != "" {
-		dir := flagDownload
-		if dir == "" {
-			var err error
-			dir, err = cfg.ExpandDownloadDir()
-			if err != nil {
-				return fmt.Errorf("resolving download dir: %w", err)
-			}
-		}
-		outputPath, err := download.Download(stream, title, dir, subFile)
-		if err != nil {
-			return err
-		}
-		fmt.Fprintf(os.Stderr, "Downloaded: %s\n", outputPath)
-		return nil
-	}
-
-	// Play
-	var startPos float64
-	if flagContinue && cfg.History {
-		entries, _ := history.Load()
-		for _, e := range entries {
-			if e.ID == selected.ID && e.Season == season && e.Episode == episode {
-				startPos = e.Position
-				debugf("resuming from position: %.0fs", startPos)
-				break
-			}
-		}
-	}
-
-	p2 := player.New(cfg.Player)
-	if !p2.Available() {
-		return fmt.Errorf("player %q not found in PATH", cfg.Player)
-	}
-
-	lastPos, err := p2.Play(stream, title, startPos, subFile)
-	if err != nil {
-		return fmt.Errorf("playback failed: %w", err)
-	}
-
-	// Save to history
-	if cfg.History {
-		entry := media.HistoryEntry{
-			ID:       selected.ID,
-			Title:    selected.Title,
-			Type:     selected.Type,
-			Season:   season,
-			Episode:  episode,
-			Position: lastPos,
-		}
-		if err := history.Save(entry); err != nil {
-			debugf("saving history failed: %v", err)
-		}
-	}
-
-	return nil
-}
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lobster/internal/download"
+	"lobster/internal/extract"
+	"lobster/internal/history"
+	"lobster/internal/media"
+	"lobster/internal/nfo"
+	"lobster/internal/player"
+	"lobster/internal/provider"
+	"lobster/internal/subtitle"
+	"lobster/internal/tmdb"
+)
+
+// tmdbClient builds a TMDB client from the loaded config. It's always
+// usable: with no tmdb_api_key set, every enrichment call is a no-op.
+func tmdbClient() *tmdb.Client {
+	return tmdb.New(cfg.TMDBAPIKey, cfg.TMDBLanguage)
+}
+
+// searchRun is the default command: lobster <query>
+func searchRun(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+
+	if query == "" {
+		// Prompt for query via fzf
+		var err error
+		query, err = selector.Input("Search")
+		if err != nil {
+			return fmt.Errorf("no search query provided")
+		}
+	}
+
+	debugf("searching for: %s", query)
+
+	ctx, cancel := rootCtx()
+	defer cancel()
+
+	p, err := provider.Default.New(cfg.Site, cfg.Base)
+	if err != nil {
+		return fmt.Errorf("selecting provider: %w", err)
+	}
+	return playFlow(ctx, p, query)
+}
+
+// playFlow handles the full search -> select -> play flow.
+func playFlow(ctx context.Context, p provider.Provider, query string) error {
+	results, lastPage, fetchPage, err := searchResults(ctx, p, query)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	tmdbClient().EnrichSearchResults(ctx, results)
+
+	results = filterResults(results)
+	if len(results) == 0 {
+		return fmt.Errorf("no results found")
+	}
+
+	selected, err := selectPaged("Select", results, lastPage, fetchPage)
+	if err != nil {
+		return err
+	}
+
+	debugf("selected: %s (ID: %s, type: %s)", selected.Title, selected.ID, selected.Type)
+
+	return resolveAndPlay(ctx, p, selected, 0, 0)
+}
+
+// searchResults fetches a page of search results for query along with the
+// provider's total page count and a fetchPage closure for loading more, for
+// providers that implement provider.Pager. --page selects which page the
+// first fetch returns; providers without Pager support fall back to the
+// default aggregated Search, with no paging.
+func searchResults(ctx context.Context, p provider.Provider, query string) ([]media.SearchResult, int, func(int) ([]media.SearchResult, int, error), error) {
+	pager, ok := p.(provider.Pager)
+	if !ok {
+		results, err := p.Search(ctx, query)
+		return results, 1, nil, err
+	}
+
+	page := flagPage
+	if page < 1 {
+		page = 1
+	}
+	results, lastPage, err := pager.SearchPage(ctx, query, page)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	fetchPage := func(n int) ([]media.SearchResult, int, error) { return pager.SearchPage(ctx, query, n) }
+	return results, lastPage, fetchPage, nil
+}
+
+// resolveAndPlay handles season/episode selection for TV and then plays.
+func resolveAndPlay(ctx context.Context, p provider.Provider, selected media.SearchResult, season, episode int) error {
+	episodeID := ""
+	title := selected.Title
+
+	// Populated inside the TV branch below; kept in scope afterwards so the
+	// mpv next/previous-episode keybindings can walk the same list without
+	// refetching it.
+	var episodes []media.Episode
+	episodeIdx := -1
+
+	tc := tmdbClient()
+	if selected.TMDBID == 0 {
+		enriched := []media.SearchResult{selected}
+		tc.EnrichSearchResults(ctx, enriched)
+		selected = enriched[0]
+	}
+
+	if selected.Type == media.TV {
+		// Get seasons
+		seasons, err := p.GetSeasons(ctx, selected.ID)
+		if err != nil {
+			return fmt.Errorf("getting seasons: %w", err)
+		}
+
+		if len(seasons) == 0 {
+			return fmt.Errorf("no seasons found")
+		}
+
+		// Select season (or use provided)
+		seasonIdx := 0
+		if season > 0 {
+			for i, s := range seasons {
+				if s.Number == season {
+					seasonIdx = i
+					break
+				}
+			}
+		} else {
+			seasonItems := make([]string, len(seasons))
+			for i, s := range seasons {
+				seasonItems[i] = fmt.Sprintf("Season %d", s.Number)
+			}
+			seasonIdx, err = selector.Select("Season", seasonItems)
+			if err != nil {
+				return err
+			}
+		}
+
+		selectedSeason := seasons[seasonIdx]
+		debugf("season: %d (ID: %s)", selectedSeason.Number, selectedSeason.ID)
+
+		// Get episodes
+		episodes, err = p.GetEpisodes(ctx, selected.ID, selectedSeason.ID)
+		if err != nil {
+			return fmt.Errorf("getting episodes: %w", err)
+		}
+
+		if len(episodes) == 0 {
+			return fmt.Errorf("no episodes found")
+		}
+
+		tc.EnrichEpisodes(ctx, selected.TMDBID, selectedSeason.Number, episodes)
+
+		// Select episode (or use provided)
+		episodeIdx = 0
+		if episode > 0 {
+			for i, ep := range episodes {
+				if ep.Number == episode {
+					episodeIdx = i
+					break
+				}
+			}
+		} else {
+			episodeItems := make([]string, len(episodes))
+			for i, ep := range episodes {
+				episodeItems[i] = provider.FormatEpisodeTitle(selected.Title, selectedSeason.Number, ep)
+			}
+			episodeIdx, err = selector.Select("Episode", episodeItems)
+			if err != nil {
+				return err
+			}
+		}
+
+		selectedEpisode := episodes[episodeIdx]
+		episodeID = selectedEpisode.ID
+		title = fmt.Sprintf("%s S%02dE%02d", selected.Title, selectedSeason.Number, selectedEpisode.Number)
+		season = selectedSeason.Number
+		episode = selectedEpisode.Number
+
+		debugf("episode: %d (ID: %s)", selectedEpisode.Number, episodeID)
+	}
+
+	// Torrent source: skip the embed/extractor pipeline and stream a
+	// magnet URI from a Torznab indexer instead.
+	if flagSource == "torrent" {
+		return torrentFlow(ctx, title, season, episode)
+	}
+
+	stream, err := resolveEpisodeStream(ctx, p, selected.ID, episodeID)
+	if err != nil {
+		var magnetErr *magnetStreamError
+		if errors.As(err, &magnetErr) {
+			tp := player.NewTorrentPlayer()
+			if !tp.Available() {
+				return fmt.Errorf("no torrent-streaming binary found in PATH (tried: webtorrent, peerflix)")
+			}
+			return tp.PlayMagnet(ctx, magnetErr.uri, cfg.Player)
+		}
+		return err
+	}
+	debugf("stream URL: %s", stream.URL)
+
+	// JSON output mode
+	if flagJSON {
+		out := map[string]interface{}{
+			"title":     title,
+			"url":       stream.URL,
+			"quality":   stream.Quality,
+			"subtitles": stream.Subtitles,
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	subFile := resolveSubtitle(stream)
+
+	// Download mode
+	if flagDownload != "" {
+		dir := flagDownload
+		if dir == "" {
+			var err error
+			dir, err = cfg.ExpandDownloadDir()
+			if err != nil {
+				return fmt.Errorf("resolving download dir: %w", err)
+			}
+		}
+		subFiles, subLanguages := resolveSubtitlesForDownload(stream, subFile)
+		outputPath, err := download.Download(stream, title, dir, subFiles, download.Options{
+			Concurrency:    flagConcurrency,
+			Resume:         flagResume,
+			Retries:        cfg.DownloadRetries,
+			NamingTemplate: cfg.DownloadNamingTemplate,
+			LayoutFormat:   cfg.DownloadLayoutFormat,
+			Season:         season,
+			Episode:        episode,
+			Year:           selected.Year,
+			Languages:      subLanguages,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Downloaded: %s\n", outputPath)
+
+		if flagNFO {
+			if err := writeNFO(ctx, p, selected, outputPath, season, episode); err != nil {
+				debugf("writing .nfo failed: %v", err)
+			}
+		}
+		return nil
+	}
+
+	// Play
+	var startPos float64
+	if flagContinue && cfg.History {
+		entries, _ := history.Load()
+		for _, e := range entries {
+			if e.ID == selected.ID && e.Season == season && e.Episode == episode {
+				startPos = e.Position
+				debugf("resuming from position: %.0fs", startPos)
+				break
+			}
+		}
+	}
+
+	p2 := player.New(cfg.Player)
+	if !p2.Available() {
+		return fmt.Errorf("player %q not found in PATH", cfg.Player)
+	}
+
+	// TV content with a resolved episode list gets next/previous-episode,
+	// mark-watched, and subtitle-cycle keybindings wired into the same mpv
+	// process, so the user never has to leave it between episodes.
+	if hookable, ok := p2.(player.HookablePlayer); ok && selected.Type == media.TV && episodeIdx >= 0 {
+		hookable.SetHooks(episodeHooks(ctx, p, selected, season, episodes, episodeIdx, stream))
+	}
+
+	lastPos, err := p2.Play(stream, title, startPos, subFile)
+	if err != nil {
+		return fmt.Errorf("playback failed: %w", err)
+	}
+
+	// Save to history
+	if cfg.History {
+		entry := media.HistoryEntry{
+			ID:       selected.ID,
+			Title:    selected.Title,
+			Type:     selected.Type,
+			Season:   season,
+			Episode:  episode,
+			Position: lastPos,
+		}
+		if err := history.Save(entry); err != nil {
+			debugf("saving history failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// magnetStreamError signals that the selected server resolved to a magnet
+// URI (the torznab provider) rather than an embed page for extract.Default
+// to decrypt. resolveAndPlay unwraps it to bypass the ffmpeg/mpv-direct
+// pipeline and hand the magnet to player.TorrentPlayer instead.
+type magnetStreamError struct {
+	uri string
+}
+
+func (e *magnetStreamError) Error() string {
+	return fmt.Sprintf("magnet stream: %s", e.uri)
+}
+
+// resolveEpisodeStream runs the server-selection/embed/extractor pipeline
+// for a single episode (or, when episodeID is "", a movie). It's shared by
+// the initial play and by the mpv next/previous-episode keybindings so
+// both go through the exact same path to a playable stream.
+func resolveEpisodeStream(ctx context.Context, p provider.Provider, contentID, episodeID string) (*media.Stream, error) {
+	servers, err := p.GetServers(ctx, contentID, episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("getting servers: %w", err)
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no servers found")
+	}
+
+	serverIdx := 0
+	for i, s := range servers {
+		if strings.EqualFold(s.Name, cfg.Provider) {
+			serverIdx = i
+			break
+		}
+	}
+	debugf("using server: %s (ID: %s)", servers[serverIdx].Name, servers[serverIdx].ID)
+
+	embedURL, err := p.GetEmbedURL(ctx, servers[serverIdx].ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting embed URL: %w", err)
+	}
+	debugf("embed URL: %s", embedURL)
+
+	// torznab's GetEmbedURL returns a magnet URI, not an embed page: there's
+	// no extractor for it, so hand it straight back to resolveAndPlay to
+	// route through player.TorrentPlayer instead.
+	if strings.HasPrefix(embedURL, "magnet:") {
+		return nil, &magnetStreamError{uri: embedURL}
+	}
+
+	ext, err := extract.Default.Resolve(embedURL)
+	if err != nil {
+		return nil, fmt.Errorf("finding extractor: %w", err)
+	}
+	stream, err := ext.Extract(ctx, embedURL, cfg.Quality)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting stream: %w", err)
+	}
+
+	return stream, nil
+}
+
+// resolveSubtitle downloads and normalizes the best-match subtitle for
+// stream, if any. The temp file it returns is intentionally left for the
+// OS to reap rather than cleaned up immediately: it may still be loading
+// in mpv by the time this returns, including when called from a
+// next/previous-episode hook well after the initial Download/Cleanup
+// pairing would have run.
+func resolveSubtitle(stream *media.Stream) string {
+	if flagNoSubs || len(stream.Subtitles) == 0 {
+		return ""
+	}
+	best := subtitle.BestMatch(stream.Subtitles, cfg.SubsLanguage)
+	if best == nil {
+		return ""
+	}
+	tmpDir, err := subtitle.NewTempDir()
+	if err != nil {
+		return ""
+	}
+	subFile, err := tmpDir.Download(*best)
+	if err != nil {
+		debugf("subtitle download failed: %v", err)
+		return ""
+	}
+	debugf("subtitle file: %s", subFile)
+	return subFile
+}
+
+// resolveSubtitlesForDownload builds the subtitle files and matching
+// language names to mux into --download output. With --subs-languages
+// unset, it reuses the single subFile/cfg.SubsLanguage pair resolveSubtitle
+// already resolved for playback, so plain --download behaves exactly as
+// before. With --subs-languages set, it downloads one track per requested
+// language via subtitle.BestMatchMulti, skipping any language with no
+// matching subtitle rather than failing the whole download.
+func resolveSubtitlesForDownload(stream *media.Stream, subFile string) ([]string, []string) {
+	if flagNoSubs || len(stream.Subtitles) == 0 {
+		return nil, nil
+	}
+	if flagSubsLanguages == "" {
+		if subFile == "" {
+			return nil, nil
+		}
+		return []string{subFile}, []string{cfg.SubsLanguage}
+	}
+
+	var languages []string
+	for _, l := range strings.Split(flagSubsLanguages, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			languages = append(languages, l)
+		}
+	}
+
+	matches := subtitle.BestMatchMulti(stream.Subtitles, languages)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	tmpDir, err := subtitle.NewTempDir()
+	if err != nil {
+		return nil, nil
+	}
+
+	var files, langs []string
+	for _, m := range matches {
+		f, err := tmpDir.Download(m)
+		if err != nil {
+			debugf("subtitle download failed for %s: %v", m.Language, err)
+			continue
+		}
+		files = append(files, f)
+		langs = append(langs, m.Language)
+	}
+	return files, langs
+}
+
+// writeNFO fetches the content's detail page and emits a Kodi-compatible
+// .nfo plus poster/fanart images next to outputPath, for libraries that
+// expect one (Kodi, Jellyfin). Errors are the caller's to decide whether
+// to surface, since a missing .nfo shouldn't fail an otherwise-successful
+// download.
+func writeNFO(ctx context.Context, p provider.Provider, selected media.SearchResult, outputPath string, season, episode int) error {
+	detail, err := p.GetDetails(ctx, selected.ID)
+	if err != nil {
+		return fmt.Errorf("getting details: %w", err)
+	}
+	return nfo.Write(ctx, filepath.Dir(outputPath), selected.Title, selected.Year, detail, season, episode)
+}
+
+// episodeHooks builds the mpv PlaybackHooks for a TV episode session:
+// Shift+N/Shift+P step forward/back through episodes (fetching and
+// extracting the stream fresh for each one), w force-marks the current
+// episode watched, and s cycles through the playing stream's subtitle
+// tracks. idx is mutated as Next/Previous are used so repeated presses
+// keep walking the list instead of bouncing between two episodes.
+func episodeHooks(ctx context.Context, p provider.Provider, selected media.SearchResult, seasonNum int, episodes []media.Episode, idx int, initialStream *media.Stream) *player.PlaybackHooks {
+	subs := initialStream.Subtitles
+	subIdx := -1
+
+	step := func(next bool) (*media.Stream, string, string, error) {
+		newIdx := idx + 1
+		if !next {
+			newIdx = idx - 1
+		}
+		if newIdx < 0 || newIdx >= len(episodes) {
+			return nil, "", "", fmt.Errorf("no episode in that direction")
+		}
+		idx = newIdx
+
+		ep := episodes[idx]
+		stream, err := resolveEpisodeStream(ctx, p, selected.ID, ep.ID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		subs = stream.Subtitles
+		subIdx = -1
+
+		title := provider.FormatEpisodeTitle(selected.Title, seasonNum, ep)
+		return stream, title, resolveSubtitle(stream), nil
+	}
+
+	return &player.PlaybackHooks{
+		OnNext:     func() (*media.Stream, string, string, error) { return step(true) },
+		OnPrevious: func() (*media.Stream, string, string, error) { return step(false) },
+		OnMarkWatched: func(position, duration float64) {
+			if !cfg.History {
+				return
+			}
+			pos := position
+			if duration > 0 {
+				pos = duration // force-marking watched means "fully watched"
+			}
+			entry := media.HistoryEntry{
+				ID:       selected.ID,
+				Title:    selected.Title,
+				Type:     selected.Type,
+				Season:   seasonNum,
+				Episode:  episodes[idx].Number,
+				Position: pos,
+				Duration: duration,
+			}
+			if err := history.Save(entry); err != nil {
+				debugf("saving history failed: %v", err)
+			}
+		},
+		OnCycleSubtitle: func() string {
+			if len(subs) == 0 {
+				return ""
+			}
+			subIdx = (subIdx + 1) % len(subs)
+			return subs[subIdx].URL
+		},
+	}
+}
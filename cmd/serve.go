@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"lobster/internal/server"
+)
+
+var flagServeAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a local HTTP/JSON API server mirroring the Provider interface",
+	Long: `Serve mounts REST endpoints for search, seasons, episodes, and stream
+resolution, plus /play and /download to trigger playback non-interactively.
+It's meant for embedding lobster in home-media dashboards or other
+front-ends. Requires api_token to be set in the config file; every request
+must present it as "Authorization: Bearer <token>".`,
+	RunE: serveRun,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&flagServeAddr, "addr", ":8080", "Address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func serveRun(cmd *cobra.Command, args []string) error {
+	srv, err := server.New(server.Config{
+		Addr:                   flagServeAddr,
+		Token:                  cfg.APIToken,
+		Site:                   cfg.Site,
+		Base:                   cfg.Base,
+		ServerName:             cfg.Provider,
+		Quality:                cfg.Quality,
+		Player:                 cfg.Player,
+		DownloadDir:            mustExpandDownloadDir(),
+		DownloadRetries:        cfg.DownloadRetries,
+		DownloadNamingTemplate: cfg.DownloadNamingTemplate,
+		DownloadLayoutFormat:   cfg.DownloadLayoutFormat,
+		CORSOrigin:             cfg.ServeCORSOrigin,
+	})
+	if err != nil {
+		return fmt.Errorf("starting server: %w", err)
+	}
+
+	ctx, cancel := rootCtx()
+	defer cancel()
+
+	fmt.Printf("Listening on %s\n", flagServeAddr)
+	return srv.ListenAndServe(ctx)
+}
+
+// mustExpandDownloadDir resolves cfg's download dir, falling back to the
+// raw configured value if expansion fails (e.g. no home directory) so
+// serve can still start; /download requests can still override it
+// per-request.
+func mustExpandDownloadDir() string {
+	dir, err := cfg.ExpandDownloadDir()
+	if err != nil {
+		return cfg.DownloadDir
+	}
+	return dir
+}
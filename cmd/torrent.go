@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"lobster/internal/player"
+	"lobster/internal/torrent"
+)
+
+// torrentFlow searches the configured Torznab indexer for title (plus
+// season/episode for TV) and hands the best-ranked magnet URI to an
+// external torrent-to-player bridge, bypassing the embed/extractor
+// pipeline entirely.
+func torrentFlow(ctx context.Context, title string, season, episode int) error {
+	if cfg.TorrentIndexerURL == "" {
+		return fmt.Errorf("--source torrent requires torrent_indexer_url (and torrent_indexer_api_key) in config")
+	}
+
+	client := torrent.New(cfg.TorrentIndexerURL, cfg.TorrentIndexerAPIKey)
+	results, err := client.Search(ctx, torrent.SearchParams{
+		Query:           title,
+		Season:          season,
+		Episode:         episode,
+		CheckFileSize:   true,
+		CheckResolution: true,
+		MinSeeders:      1,
+	})
+	if err != nil {
+		return fmt.Errorf("torrent search failed: %w", err)
+	}
+
+	best := results[0]
+	debugf("torrent: %s (seeders: %d, resolution: %s)", best.Title, best.Seeders, best.Release.Resolution)
+
+	tp := player.NewTorrentPlayer()
+	if !tp.Available() {
+		return fmt.Errorf("no torrent-streaming binary found in PATH (tried: webtorrent, peerflix)")
+	}
+	return tp.PlayMagnet(ctx, best.MagnetURI, cfg.Player)
+}
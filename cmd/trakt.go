@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"lobster/internal/history"
+	"lobster/internal/trakt"
+)
+
+var traktCmd = &cobra.Command{
+	Use:   "trakt",
+	Short: "Manage Trakt.tv account sync",
+}
+
+var traktAuthCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Authenticate with Trakt.tv via device code",
+	RunE:  traktAuthRun,
+}
+
+func init() {
+	traktCmd.AddCommand(traktAuthCmd)
+}
+
+func traktAuthRun(cmd *cobra.Command, args []string) error {
+	client, err := trakt.New()
+	if err != nil {
+		return fmt.Errorf("initializing trakt client: %w", err)
+	}
+
+	if err := client.Authenticate(); err != nil {
+		return fmt.Errorf("trakt authentication failed: %w", err)
+	}
+
+	fmt.Println("Trakt.tv authentication successful.")
+	return nil
+}
+
+// setupTraktSync wires the Trakt remote sync backend into the history
+// package when trakt_sync is enabled and credentials are present.
+func setupTraktSync() {
+	if cfg == nil || !cfg.TraktSync {
+		return
+	}
+
+	client, err := trakt.New()
+	if err != nil || !client.Authenticated() {
+		debugf("trakt sync enabled but not authenticated; run `lobster trakt auth`")
+		return
+	}
+
+	history.SetRemoteSync(client)
+}
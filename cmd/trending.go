@@ -1,92 +1,122 @@
-package cmd
-
-import (
-	"fmt"
-	"strings"
-
-	"github.com/spf13/cobra"
-
-	"lobster/internal/media"
-	"lobster/internal/provider"
-	"lobster/internal/ui"
-)
-
-var trendingCmd = &cobra.Command{
-	Use:   "trending [movies|tv]",
-	Short: "Browse trending content",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  trendingRun,
-}
-
-func trendingRun(cmd *cobra.Command, args []string) error {
-	mediaType := parseMediaTypeArg(args)
-
-	p := provider.NewFlixHQ(cfg.Base)
-	results, err := p.Trending(mediaType)
-	if err != nil {
-		return fmt.Errorf("getting trending: %w", err)
-	}
-
-	if len(results) == 0 {
-		fmt.Println("No trending content found.")
-		return nil
-	}
-
-	items := make([]string, len(results))
-	for i, r := range results {
-		items[i] = provider.FormatDisplayTitle(r)
-	}
-
-	idx, err := ui.Select("Trending", items)
-	if err != nil {
-		return err
-	}
-
-	return resolveAndPlay(p, results[idx], 0, 0)
-}
-
-var recentCmd = &cobra.Command{
-	Use:   "recent [movies|tv]",
-	Short: "Browse recently added content",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  recentRun,
-}
-
-func recentRun(cmd *cobra.Command, args []string) error {
-	mediaType := parseMediaTypeArg(args)
-
-	p := provider.NewFlixHQ(cfg.Base)
-	results, err := p.Recent(mediaType)
-	if err != nil {
-		return fmt.Errorf("getting recent: %w", err)
-	}
-
-	if len(results) == 0 {
-		fmt.Println("No recently added content found.")
-		return nil
-	}
-
-	items := make([]string, len(results))
-	for i, r := range results {
-		items[i] = provider.FormatDisplayTitle(r)
-	}
-
-	idx, err := ui.Select("Recent", items)
-	if err != nil {
-		return err
-	}
-
-	return resolveAndPlay(p, results[idx], 0, 0)
-}
-
-func parseMediaTypeArg(args []string) media.MediaType {
-	if len(args) == 0 {
-		return media.Movie // Default
-	}
-	switch strings.ToLower(args[0]) {
-	case "tv", "shows", "series":
-		return media.TV
-	default:
-		return media.Movie
-	}
-}
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lobster/internal/media"
+	"lobster/internal/provider"
+)
+
+var trendingCmd = &cobra.Command{
+	Use:   "trending [movies|tv]",
+	Short: "Browse trending content",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  trendingRun,
+}
+
+func trendingRun(cmd *cobra.Command, args []string) error {
+	mediaType := parseMediaTypeArg(args)
+
+	ctx, cancel := rootCtx()
+	defer cancel()
+
+	p, err := provider.Default.New(cfg.Site, cfg.Base)
+	if err != nil {
+		return fmt.Errorf("selecting provider: %w", err)
+	}
+	results, err := p.Trending(ctx, mediaType)
+	if err != nil {
+		return fmt.Errorf("getting trending: %w", err)
+	}
+	results = filterResults(results)
+
+	if len(results) == 0 {
+		fmt.Println("No trending content found.")
+		return nil
+	}
+
+	// Trending (the /home page) isn't paginated upstream, so there's no
+	// Pager fetchPage to offer here — just a plain selection.
+	selected, err := selectPaged("Trending", results, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	return resolveAndPlay(ctx, p, selected, 0, 0)
+}
+
+var recentCmd = &cobra.Command{
+	Use:   "recent [movies|tv]",
+	Short: "Browse recently added content",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  recentRun,
+}
+
+func recentRun(cmd *cobra.Command, args []string) error {
+	mediaType := parseMediaTypeArg(args)
+
+	ctx, cancel := rootCtx()
+	defer cancel()
+
+	p, err := provider.Default.New(cfg.Site, cfg.Base)
+	if err != nil {
+		return fmt.Errorf("selecting provider: %w", err)
+	}
+	results, lastPage, fetchPage, err := recentResults(ctx, p, mediaType)
+	if err != nil {
+		return fmt.Errorf("getting recent: %w", err)
+	}
+	results = filterResults(results)
+
+	if len(results) == 0 {
+		fmt.Println("No recently added content found.")
+		return nil
+	}
+
+	selected, err := selectPaged("Recent", results, lastPage, fetchPage)
+	if err != nil {
+		return err
+	}
+
+	return resolveAndPlay(ctx, p, selected, 0, 0)
+}
+
+// recentResults fetches a page of recently-added content for mediaType
+// along with the provider's total page count and a fetchPage closure for
+// loading more, for providers that implement provider.Pager. --page selects
+// which page the first fetch returns; providers without Pager support fall
+// back to the default single-page Recent, with no paging.
+func recentResults(ctx context.Context, p provider.Provider, mediaType media.MediaType) ([]media.SearchResult, int, func(int) ([]media.SearchResult, int, error), error) {
+	pager, ok := p.(provider.Pager)
+	if !ok {
+		results, err := p.Recent(ctx, mediaType)
+		return results, 1, nil, err
+	}
+
+	page := flagPage
+	if page < 1 {
+		page = 1
+	}
+	results, lastPage, err := pager.RecentPage(ctx, mediaType, page)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	fetchPage := func(n int) ([]media.SearchResult, int, error) { return pager.RecentPage(ctx, mediaType, n) }
+	return results, lastPage, fetchPage, nil
+}
+
+func parseMediaTypeArg(args []string) media.MediaType {
+	if len(args) == 0 {
+		return media.Movie // Default
+	}
+	switch strings.ToLower(args[0]) {
+	case "tv", "shows", "series":
+		return media.TV
+	default:
+		return media.Movie
+	}
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lobster/internal/download"
+	"lobster/internal/extract"
+	"lobster/internal/media"
+	"lobster/internal/provider"
+	"lobster/internal/watchlist"
+)
+
+var (
+	flagWatchFilter     string
+	flagWatchMaxAgeDays int
+	flagWatchDownload   bool
+	flagWatchDaemon     bool
+	flagWatchInterval   string
+)
+
+var watchlistCmd = &cobra.Command{
+	Use:   "watchlist",
+	Short: "Track shows and auto-fetch new episodes",
+}
+
+var watchlistAddCmd = &cobra.Command{
+	Use:   "add <query>",
+	Short: "Search for a show and add it to the watchlist",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  watchlistAddRun,
+}
+
+var watchlistRemoveCmd = &cobra.Command{
+	Use:   "remove <content-id>",
+	Short: "Remove an entry from the watchlist",
+	Args:  cobra.ExactArgs(1),
+	RunE:  watchlistRemoveRun,
+}
+
+var watchlistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List watchlist entries",
+	RunE:  watchlistListRun,
+}
+
+var watchlistSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Check watchlist entries for new episodes",
+	RunE:  watchlistSyncRun,
+}
+
+func init() {
+	watchlistAddCmd.Flags().StringVar(&flagWatchFilter, "filter", "", "Only notify for episode titles containing this substring")
+	watchlistAddCmd.Flags().IntVar(&flagWatchMaxAgeDays, "max-age-days", 0, "Ignore episodes aired more than N days ago (0: unlimited)")
+
+	watchlistSyncCmd.Flags().BoolVar(&flagWatchDownload, "download", false, "Download new episodes instead of just printing them")
+	watchlistSyncCmd.Flags().BoolVar(&flagWatchDaemon, "daemon", false, "Run sync repeatedly instead of once")
+	watchlistSyncCmd.Flags().StringVar(&flagWatchInterval, "interval", "1h", `Sync interval in --daemon mode (e.g. "1h", "@every 30m")`)
+
+	watchlistCmd.AddCommand(watchlistAddCmd)
+	watchlistCmd.AddCommand(watchlistRemoveCmd)
+	watchlistCmd.AddCommand(watchlistListCmd)
+	watchlistCmd.AddCommand(watchlistSyncCmd)
+
+	rootCmd.AddCommand(watchlistCmd)
+}
+
+func watchlistAddRun(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+
+	ctx, cancel := rootCtx()
+	defer cancel()
+
+	p, err := provider.Default.New(cfg.Site, cfg.Base)
+	if err != nil {
+		return fmt.Errorf("selecting provider: %w", err)
+	}
+
+	results, err := p.Search(ctx, query)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no results for %q", query)
+	}
+
+	items := make([]string, len(results))
+	for i, r := range results {
+		items[i] = provider.FormatDisplayTitle(r)
+	}
+	idx, err := selector.Select("Select", items)
+	if err != nil {
+		return err
+	}
+	selected := results[idx]
+
+	if selected.Type != media.TV {
+		return fmt.Errorf("only TV shows can be watchlisted")
+	}
+
+	entry := watchlist.Entry{
+		Provider:   cfg.Site,
+		ContentID:  selected.ID,
+		Title:      selected.Title,
+		Type:       selected.Type,
+		Filter:     flagWatchFilter,
+		MaxAgeDays: flagWatchMaxAgeDays,
+	}
+	if err := watchlist.Add(entry); err != nil {
+		return fmt.Errorf("saving watchlist entry: %w", err)
+	}
+
+	fmt.Printf("Added %q to the watchlist.\n", selected.Title)
+	return nil
+}
+
+func watchlistRemoveRun(cmd *cobra.Command, args []string) error {
+	if err := watchlist.Remove(cfg.Site, args[0]); err != nil {
+		return fmt.Errorf("removing watchlist entry: %w", err)
+	}
+	fmt.Println("Removed from the watchlist.")
+	return nil
+}
+
+func watchlistListRun(cmd *cobra.Command, args []string) error {
+	entries, err := watchlist.Load()
+	if err != nil {
+		return fmt.Errorf("loading watchlist: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("Watchlist is empty.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TITLE\tLAST SEEN\tPROVIDER\tCONTENT ID")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\tS%02dE%02d\t%s\t%s\n", e.Title, e.LastSeenSeason, e.LastSeenEpisode, e.Provider, e.ContentID)
+	}
+	return w.Flush()
+}
+
+func watchlistSyncRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := rootCtx()
+	defer cancel()
+
+	if !flagWatchDaemon {
+		return runWatchlistSync(ctx)
+	}
+
+	if err := runWatchlistSync(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "watchlist sync failed: %v\n", err)
+	}
+
+	interval, err := watchlist.ParseInterval(flagWatchInterval)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := runWatchlistSync(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "watchlist sync failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// runWatchlistSync diffs the watchlist against each show's current
+// seasons/episodes, then either prints or downloads every new episode
+// found, depending on flagWatchDownload.
+func runWatchlistSync(ctx context.Context) error {
+	newEpisodes, err := watchlist.Sync(ctx, cfg.Base, debugf)
+	if err != nil {
+		return fmt.Errorf("syncing watchlist: %w", err)
+	}
+
+	for _, ne := range newEpisodes {
+		title := fmt.Sprintf("%s S%02dE%02d", ne.Entry.Title, ne.Season, ne.Episode.Number)
+		if ne.Episode.Title != "" {
+			title += ": " + ne.Episode.Title
+		}
+
+		if !flagWatchDownload {
+			fmt.Printf("New episode: %s\n", title)
+			continue
+		}
+
+		if err := downloadWatchlistEpisode(ctx, ne, title); err != nil {
+			fmt.Fprintf(os.Stderr, "downloading %s: %v\n", title, err)
+		}
+	}
+
+	return nil
+}
+
+// downloadWatchlistEpisode resolves a new episode straight through to a
+// downloaded file, non-interactively — the same embed/extractor pipeline
+// as resolveAndPlay, minus the fzf prompts.
+func downloadWatchlistEpisode(ctx context.Context, ne watchlist.NewEpisode, title string) error {
+	p, err := provider.Default.New(ne.Entry.Provider, cfg.Base)
+	if err != nil {
+		return err
+	}
+
+	servers, err := p.GetServers(ctx, ne.Entry.ContentID, ne.Episode.ID)
+	if err != nil {
+		return fmt.Errorf("getting servers: %w", err)
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no servers found")
+	}
+
+	serverIdx := 0
+	for i, s := range servers {
+		if strings.EqualFold(s.Name, cfg.Provider) {
+			serverIdx = i
+			break
+		}
+	}
+
+	embedURL, err := p.GetEmbedURL(ctx, servers[serverIdx].ID)
+	if err != nil {
+		return fmt.Errorf("getting embed URL: %w", err)
+	}
+
+	ext, err := extract.Default.Resolve(embedURL)
+	if err != nil {
+		return fmt.Errorf("finding extractor: %w", err)
+	}
+	stream, err := ext.Extract(ctx, embedURL, cfg.Quality)
+	if err != nil {
+		return fmt.Errorf("decrypting stream: %w", err)
+	}
+
+	dir, err := cfg.ExpandDownloadDir()
+	if err != nil {
+		return fmt.Errorf("resolving download dir: %w", err)
+	}
+
+	outputPath, err := download.Download(stream, title, dir, nil, download.Options{
+		Concurrency:    download.DefaultConcurrency,
+		Retries:        cfg.DownloadRetries,
+		NamingTemplate: cfg.DownloadNamingTemplate,
+		LayoutFormat:   cfg.DownloadLayoutFormat,
+		Season:         ne.Season,
+		Episode:        ne.Episode.Number,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Downloaded: %s\n", outputPath)
+	return nil
+}
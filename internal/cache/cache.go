@@ -0,0 +1,180 @@
+// Package cache provides a simple on-disk TTL key-value store, used to
+// avoid re-fetching expensive or slow-changing network responses (embed
+// decryption keys, getSources payloads) on every run. Entries are stored
+// under XDG_CACHE_HOME/lobster/ as a data file plus a JSON metadata
+// sidecar recording when the entry was fetched, when it expires, and its
+// ETag (if any), so callers can issue conditional requests on refresh.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Dir returns the XDG-compliant base cache directory for lobster.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "lobster"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", "lobster"), nil
+}
+
+// ClearAll removes the entire on-disk cache. Used by the --clear-cache flag.
+func ClearAll() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing cache dir: %w", err)
+	}
+	return nil
+}
+
+// meta is the JSON sidecar recorded alongside each cached entry.
+type meta struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ETag      string    `json:"etag,omitempty"`
+}
+
+// Entry is a cached value returned by Store.Get.
+type Entry struct {
+	Data  []byte
+	ETag  string
+	Fresh bool // false if the entry is present but past its TTL
+}
+
+// Store is a namespaced, thread-safe TTL key-value cache backed by files
+// under XDG_CACHE_HOME/lobster/<namespace>/.
+type Store struct {
+	dir string
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// New creates (or opens) a cache store namespaced under, e.g.,
+// "megacloud-keys" or "megacloud-sources", so unrelated caches never
+// collide on key hashes.
+func New(namespace string) (*Store, error) {
+	base, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(base, namespace)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating cache dir: %w", err)
+	}
+	return &Store{dir: dir, locks: make(map[string]*sync.Mutex)}, nil
+}
+
+// lockFor returns the mutex guarding key, so a slow fetch-and-populate for
+// a given key can't race with another goroutine doing the same (a poor
+// man's single-flight).
+func (s *Store) lockFor(key string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[key] = l
+	}
+	return l
+}
+
+// paths returns the data file and metadata sidecar path for key.
+func (s *Store) paths(key string) (data, metaFile string) {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(s.dir, name+".data"), filepath.Join(s.dir, name+".meta.json")
+}
+
+// Get returns the cached value for key, if anything has been cached yet.
+// ok is false only when key has never been stored. A present but expired
+// entry is still returned, with Fresh=false, so callers can retry with a
+// conditional request (If-None-Match: entry.ETag) and fall back to it on
+// a 304.
+func (s *Store) Get(key string) (entry Entry, ok bool) {
+	lock := s.lockFor(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	dataPath, metaPath := s.paths(key)
+
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	metaRaw, err := os.ReadFile(metaPath)
+	if err != nil {
+		return Entry{}, false
+	}
+	var m meta
+	if err := json.Unmarshal(metaRaw, &m); err != nil {
+		return Entry{}, false
+	}
+
+	return Entry{Data: data, ETag: m.ETag, Fresh: time.Now().Before(m.ExpiresAt)}, true
+}
+
+// Set writes data for key to disk with the given TTL and optional ETag,
+// using atomic writes (temp file + rename) so a concurrent Get never
+// observes a torn entry.
+func (s *Store) Set(key string, data []byte, ttl time.Duration, etag string) error {
+	lock := s.lockFor(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	dataPath, metaPath := s.paths(key)
+	now := time.Now()
+
+	metaRaw, err := json.Marshal(meta{FetchedAt: now, ExpiresAt: now.Add(ttl), ETag: etag})
+	if err != nil {
+		return fmt.Errorf("marshaling cache metadata: %w", err)
+	}
+
+	if err := writeAtomic(dataPath, data); err != nil {
+		return fmt.Errorf("writing cache data: %w", err)
+	}
+	if err := writeAtomic(metaPath, metaRaw); err != nil {
+		return fmt.Errorf("writing cache metadata: %w", err)
+	}
+	return nil
+}
+
+// writeAtomic writes data to path via temp file + rename, matching the
+// pattern used by internal/history for its TSV writes.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "cache-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
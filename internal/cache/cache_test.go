@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetGetRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	s, err := New("test-ns")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if err := s.Set("k1", []byte("hello"), time.Hour, "etag-1"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	entry, ok := s.Get("k1")
+	if !ok {
+		t.Fatal("Get() ok = false, want true")
+	}
+	if string(entry.Data) != "hello" {
+		t.Errorf("Data = %q, want %q", entry.Data, "hello")
+	}
+	if entry.ETag != "etag-1" {
+		t.Errorf("ETag = %q, want %q", entry.ETag, "etag-1")
+	}
+	if !entry.Fresh {
+		t.Error("Fresh = false, want true")
+	}
+}
+
+func TestGetMissingKey(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	s, err := New("test-ns")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, ok := s.Get("missing"); ok {
+		t.Error("Get() ok = true for a key that was never set")
+	}
+}
+
+func TestExpiredEntryStillReadableButNotFresh(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	s, err := New("test-ns")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if err := s.Set("k1", []byte("stale"), -time.Minute, "etag-1"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	entry, ok := s.Get("k1")
+	if !ok {
+		t.Fatal("Get() ok = false, want true (expired entries are still readable)")
+	}
+	if entry.Fresh {
+		t.Error("Fresh = true, want false for an entry past its TTL")
+	}
+	if entry.ETag != "etag-1" {
+		t.Errorf("ETag = %q, want %q (needed for conditional refetch)", entry.ETag, "etag-1")
+	}
+}
+
+func TestNamespacesDoNotCollide(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	a, err := New("ns-a")
+	if err != nil {
+		t.Fatalf("New(ns-a) error: %v", err)
+	}
+	b, err := New("ns-b")
+	if err != nil {
+		t.Fatalf("New(ns-b) error: %v", err)
+	}
+
+	if err := a.Set("k", []byte("from-a"), time.Hour, ""); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	if _, ok := b.Get("k"); ok {
+		t.Error("Get() ok = true, want false: same key in a different namespace should not be visible")
+	}
+}
+
+func TestClearAllRemovesCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	s, err := New("test-ns")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if err := s.Set("k1", []byte("hello"), time.Hour, ""); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	if err := ClearAll(); err != nil {
+		t.Fatalf("ClearAll() error: %v", err)
+	}
+
+	if _, ok := s.Get("k1"); ok {
+		t.Error("Get() ok = true after ClearAll(), want false")
+	}
+}
@@ -5,11 +5,14 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+
+	"lobster/internal/httputil"
 )
 
 // Config holds all application configuration.
@@ -20,8 +23,79 @@ type Config struct {
 	SubsLanguage string `toml:"subs_language"`
 	Quality      string `toml:"quality"`
 	History      bool   `toml:"history"`
+	AllowCAM     bool   `toml:"allow_cam"`
 	DownloadDir  string `toml:"download_dir"`
 	Debug        bool   `toml:"debug"`
+	TraktSync    bool   `toml:"trakt_sync"`
+
+	// Site selects the content source from the provider registry (e.g.
+	// "flixhq"), distinct from Provider above, which picks a streaming
+	// server/CDN within that source.
+	Site string `toml:"site"`
+
+	// Torrent source (--source torrent): a Torznab-compatible indexer.
+	TorrentIndexerURL    string `toml:"torrent_indexer_url"`
+	TorrentIndexerAPIKey string `toml:"torrent_indexer_api_key"`
+
+	// [torznab] settings for the torznab content source (--site torznab),
+	// which surfaces a Torznab indexer's results through the normal
+	// search/select/play pipeline (see internal/provider/torznab) rather
+	// than the --source torrent bypass above, which hands a magnet
+	// straight to webtorrent/peerflix without going through
+	// provider.Provider at all.
+	Torznab TorznabConfig `toml:"torznab"`
+
+	// Optional TMDB metadata enrichment (overview, runtime, episode air
+	// dates). Disabled unless TMDBAPIKey is set.
+	TMDBAPIKey   string `toml:"tmdb_api_key"`
+	TMDBLanguage string `toml:"tmdb_language"`
+
+	// APIToken gates `lobster serve`: every request must present it as a
+	// Bearer token. The server refuses to start if this is empty.
+	APIToken string `toml:"api_token"`
+
+	// ServeCORSOrigin, when set, is sent as Access-Control-Allow-Origin by
+	// `lobster serve` so a browser-based client on a different origin can
+	// call the API. Empty (the default) disables CORS handling.
+	ServeCORSOrigin string `toml:"serve_cors_origin"`
+
+	// Download settings for `lobster download` / --download, read from the
+	// top-level download_* keys (there is no [download] table).
+	// DownloadConcurrency overrides the --concurrency default when the flag
+	// isn't given; 0 (the default) leaves the flag's default in place
+	// rather than selecting the ffmpeg-only path. Retries is the
+	// per-segment/per-range transport retry count.
+	DownloadConcurrency    int    `toml:"download_concurrency"`
+	DownloadRetries        int    `toml:"download_retries"`
+	DownloadNamingTemplate string `toml:"download_naming_template"`
+
+	// DownloadLayoutFormat selects a built-in Plex/Kodi-style directory
+	// layout ("movie", "tv", or "anime") for --download output when
+	// DownloadNamingTemplate is empty. See download.Options.LayoutFormat.
+	DownloadLayoutFormat string `toml:"download_layout_format"`
+
+	// [network] settings shared by every HTTP client lobster builds
+	// (providers, extractors, decrypt). See httputil.NetConfig for field
+	// docs; Network.MaxRetries/RetryBackoffMs overlap in spirit with
+	// DownloadRetries above but apply to transport-level retries rather
+	// than the downloader's own segment/range retry loop.
+	Network httputil.NetConfig `toml:"network"`
+
+	// [ui] settings. Backend selects the Selector ui.New builds ("fzf",
+	// "plain", "menu", "rofi", "dmenu", "wofi"); empty defers to
+	// ui.Default's auto-detection.
+	UI UIConfig `toml:"ui"`
+}
+
+// UIConfig configures the interactive selection backend (see ui.Selector).
+type UIConfig struct {
+	Backend string `toml:"backend"`
+}
+
+// TorznabConfig configures the torznab content source's indexer.
+type TorznabConfig struct {
+	IndexerURL string `toml:"indexer_url"`
+	APIKey     string `toml:"api_key"`
 }
 
 // Default returns the default configuration.
@@ -35,6 +109,14 @@ func Default() *Config {
 		History:      true,
 		DownloadDir:  "~/Videos/lobster",
 		Debug:        false,
+		TraktSync:    false,
+		Site:         "flixhq",
+		TMDBLanguage: "en-US",
+
+		DownloadRetries:        3,
+		DownloadNamingTemplate: "",
+
+		Network: httputil.DefaultNetConfig(),
 	}
 }
 
@@ -97,24 +179,75 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("unsupported player %q (valid: mpv, vlc, iina, celluloid)", c.Player)
 	}
 
-	validProviders := map[string]bool{
-		"vidcloud": true, "upcloud": true,
-	}
-	if !validProviders[strings.ToLower(c.Provider)] {
-		return fmt.Errorf("unsupported provider %q (valid: Vidcloud, UpCloud)", c.Provider)
+	// HiAnime's server names aren't a fixed set (they're "<name> (SUB)" /
+	// "<name> (DUB)" pairs scraped per-episode), so Provider is only
+	// validated against the closed Vidcloud/UpCloud set for sites whose
+	// server list is itself fixed.
+	if c.Site != "hianime" {
+		validProviders := map[string]bool{
+			"vidcloud": true, "upcloud": true,
+		}
+		if !validProviders[strings.ToLower(c.Provider)] {
+			return fmt.Errorf("unsupported provider %q (valid: Vidcloud, UpCloud)", c.Provider)
+		}
 	}
 
 	validQualities := map[string]bool{
-		"360": true, "480": true, "720": true, "1080": true,
+		"360": true, "480": true, "720": true, "1080": true, "2160": true,
+		"best": true, "worst": true,
 	}
-	if !validQualities[c.Quality] {
-		return fmt.Errorf("unsupported quality %q (valid: 360, 480, 720, 1080)", c.Quality)
+	if !validQualities[strings.ToLower(c.Quality)] {
+		return fmt.Errorf("unsupported quality %q (valid: 360, 480, 720, 1080, 2160, best, worst)", c.Quality)
 	}
 
 	if c.Base == "" {
 		return fmt.Errorf("base URL cannot be empty")
 	}
 
+	if c.DownloadConcurrency < 0 {
+		return fmt.Errorf("download_concurrency cannot be negative")
+	}
+	if c.DownloadRetries < 0 {
+		return fmt.Errorf("download_retries cannot be negative")
+	}
+	if c.DownloadNamingTemplate != "" && !strings.Contains(c.DownloadNamingTemplate, "{title}") {
+		return fmt.Errorf("download_naming_template must reference {title}")
+	}
+
+	validLayouts := map[string]bool{"": true, "movie": true, "tv": true, "anime": true}
+	if !validLayouts[strings.ToLower(c.DownloadLayoutFormat)] {
+		return fmt.Errorf("unsupported download_layout_format %q (valid: movie, tv, anime)", c.DownloadLayoutFormat)
+	}
+
+	if c.Network.RequestTimeoutSec < 0 {
+		return fmt.Errorf("network.request_timeout_sec cannot be negative")
+	}
+	if c.Network.TotalTimeoutSec < -1 {
+		return fmt.Errorf("network.total_timeout_sec must be -1 (disabled) or a non-negative number of seconds")
+	}
+	if c.Network.MaxRetries < 0 {
+		return fmt.Errorf("network.max_retries cannot be negative")
+	}
+	if c.Network.RetryBackoffMs < 0 {
+		return fmt.Errorf("network.retry_backoff_ms cannot be negative")
+	}
+	if c.Network.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("network.max_concurrent_requests cannot be negative")
+	}
+	if c.Network.ProxyURL != "" {
+		if _, err := url.Parse(c.Network.ProxyURL); err != nil {
+			return fmt.Errorf("network.proxy_url: %w", err)
+		}
+	}
+
+	validUIBackends := map[string]bool{
+		"": true, "fzf": true, "plain": true, "menu": true,
+		"rofi": true, "dmenu": true, "wofi": true,
+	}
+	if !validUIBackends[strings.ToLower(c.UI.Backend)] {
+		return fmt.Errorf("unsupported ui.backend %q (valid: fzf, plain, menu, rofi, dmenu, wofi)", c.UI.Backend)
+	}
+
 	return nil
 }
 
@@ -143,3 +276,24 @@ func HistoryPath() (string, error) {
 	}
 	return filepath.Join(dataDir, "lobster", "history.tsv"), nil
 }
+
+// WatchlistPath returns the path to the watchlist state file.
+func WatchlistPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watchlist.json"), nil
+}
+
+// HooksConfigPath returns the path to the post-download hooks config file
+// (see download.LoadHooksConfig), kept separate from config.toml so hook
+// credentials (Plex tokens, Kodi passwords) can be managed/permissioned
+// independently.
+func HooksConfigPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hooks.toml"), nil
+}
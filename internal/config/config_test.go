@@ -36,6 +36,23 @@ func TestValidate(t *testing.T) {
 		{"valid vlc", func(c *Config) { c.Player = "vlc" }, false},
 		{"valid upcloud", func(c *Config) { c.Provider = "UpCloud" }, false},
 		{"valid 720", func(c *Config) { c.Quality = "720" }, false},
+		{"negative download concurrency", func(c *Config) { c.DownloadConcurrency = -1 }, true},
+		{"negative download retries", func(c *Config) { c.DownloadRetries = -1 }, true},
+		{"naming template missing title", func(c *Config) { c.DownloadNamingTemplate = "{season}" }, true},
+		{"valid naming template", func(c *Config) { c.DownloadNamingTemplate = "{title}/{title} S{season:02}E{episode:02}" }, false},
+		{"valid 2160", func(c *Config) { c.Quality = "2160" }, false},
+		{"valid best", func(c *Config) { c.Quality = "best" }, false},
+		{"valid worst", func(c *Config) { c.Quality = "worst" }, false},
+		{"negative network request timeout", func(c *Config) { c.Network.RequestTimeoutSec = -1 }, true},
+		{"network total timeout -1 disables", func(c *Config) { c.Network.TotalTimeoutSec = -1 }, false},
+		{"network total timeout below -1", func(c *Config) { c.Network.TotalTimeoutSec = -2 }, true},
+		{"negative network max retries", func(c *Config) { c.Network.MaxRetries = -1 }, true},
+		{"negative network retry backoff", func(c *Config) { c.Network.RetryBackoffMs = -1 }, true},
+		{"negative network max concurrent requests", func(c *Config) { c.Network.MaxConcurrentRequests = -1 }, true},
+		{"invalid network proxy url", func(c *Config) { c.Network.ProxyURL = "://bad" }, true},
+		{"valid network proxy url", func(c *Config) { c.Network.ProxyURL = "http://127.0.0.1:8080" }, false},
+		{"valid ui backend plain", func(c *Config) { c.UI.Backend = "plain" }, false},
+		{"invalid ui backend", func(c *Config) { c.UI.Backend = "xterm" }, true},
 	}
 
 	for _, tt := range tests {
@@ -6,30 +6,39 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"lobster/internal/httputil"
 	"lobster/internal/media"
+	"lobster/internal/metadata"
 )
 
 // Decryptor resolves embed URLs into playable streams.
 type Decryptor struct {
 	client *http.Client
 	apiURL string
+
+	// AllowCAM permits selectQuality to return cam/telesync sources
+	// instead of filtering them out. Mirrors config.AllowCAM; off by
+	// default since those rips are rarely what anyone wants.
+	AllowCAM bool
 }
 
-// New creates a new Decryptor.
-func New() *Decryptor {
+// New creates a new Decryptor using netCfg for request timeouts, retries,
+// and proxying. Pass httputil.DefaultNetConfig() for the previous
+// hardcoded behavior.
+func New(netCfg httputil.NetConfig) *Decryptor {
 	return &Decryptor{
-		client: httputil.NewClient(),
+		client: httputil.NewClient(netCfg),
 		apiURL: "https://api.consumet.org",
 	}
 }
 
-// NewWithAPI creates a Decryptor with a custom API URL.
-func NewWithAPI(apiURL string) *Decryptor {
+// NewWithAPI creates a Decryptor with a custom API URL and netCfg.
+func NewWithAPI(apiURL string, netCfg httputil.NetConfig) *Decryptor {
 	return &Decryptor{
-		client: httputil.NewClient(),
+		client: httputil.NewClient(netCfg),
 		apiURL: strings.TrimRight(apiURL, "/"),
 	}
 }
@@ -87,7 +96,7 @@ func (d *Decryptor) Decrypt(embedURL string, preferredQuality string) (*media.St
 	}
 
 	// Select best matching quality
-	streamURL := selectQuality(resp.Sources, preferredQuality)
+	selected, tags := selectQuality(resp.Sources, preferredQuality, d.AllowCAM)
 
 	// Convert subtitles
 	var subtitles []media.Subtitle
@@ -103,34 +112,133 @@ func (d *Decryptor) Decrypt(embedURL string, preferredQuality string) (*media.St
 	}
 
 	return &media.Stream{
-		URL:       streamURL,
+		URL:       selected,
 		Subtitles: subtitles,
 		Quality:   preferredQuality,
+		Tags:      tags,
 	}, nil
 }
 
-// selectQuality picks the best source matching the preferred quality.
+// apiSource mirrors apiResponse.Sources' anonymous struct, named so
+// selectQuality can be a normal top-level function instead of a closure.
+type apiSource struct {
+	URL     string `json:"url"`
+	Quality string `json:"quality"`
+	IsM3U8  bool   `json:"isM3U8"`
+}
+
+// selectQuality scores each source's Quality string with metadata.Parse
+// and picks the best match for preferred: an exact resolution match,
+// otherwise the closest lower resolution, otherwise "auto" (adaptive),
+// otherwise whatever scores highest. Cam/telesync sources are excluded
+// unless allowCAM is set. It returns the chosen URL along with its parsed
+// tags, so callers can surface what was actually picked.
 func selectQuality(sources []struct {
 	URL     string `json:"url"`
 	Quality string `json:"quality"`
 	IsM3U8  bool   `json:"isM3U8"`
-}, preferred string) string {
-	// First try exact match
+}, preferred string, allowCAM bool) (string, metadata.Release) {
+	type candidate struct {
+		source apiSource
+		tags   metadata.Release
+	}
+
+	var candidates []candidate
 	for _, s := range sources {
-		if strings.Contains(s.Quality, preferred) {
-			return s.URL
+		tags := metadata.Parse(s.Quality)
+		if tags.LowQuality && !allowCAM {
+			continue
 		}
+		candidates = append(candidates, candidate{source: apiSource(s), tags: tags})
+	}
+	if len(candidates) == 0 {
+		// Every source was filtered as cam/telesync; better to play
+		// something than nothing.
+		return sources[0].URL, metadata.Parse(sources[0].Quality)
 	}
 
-	// Fall back to "auto" quality (adaptive)
-	for _, s := range sources {
-		if strings.EqualFold(s.Quality, "auto") {
-			return s.URL
+	preferred = strings.ToLower(strings.TrimSpace(preferred))
+	switch preferred {
+	case "best", "":
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if metadata.Score(c.tags) > metadata.Score(best.tags) {
+				best = c
+			}
+		}
+		return best.source.URL, best.tags
+	case "worst":
+		worst := candidates[0]
+		for _, c := range candidates[1:] {
+			if metadata.Score(c.tags) < metadata.Score(worst.tags) {
+				worst = c
+			}
+		}
+		return worst.source.URL, worst.tags
+	}
+
+	wantHeight, wantIsNumeric := parseResolutionPreference(preferred)
+
+	// Exact height match.
+	for _, c := range candidates {
+		if wantIsNumeric && resolutionHeight(c.tags.Resolution) == wantHeight {
+			return c.source.URL, c.tags
+		}
+		if !wantIsNumeric && strings.Contains(c.source.Quality, preferred) {
+			return c.source.URL, c.tags
+		}
+	}
+
+	// Closest lower resolution.
+	if wantIsNumeric {
+		var closest *candidate
+		for i, c := range candidates {
+			h := resolutionHeight(c.tags.Resolution)
+			if h == 0 || h > wantHeight {
+				continue
+			}
+			if closest == nil || h > resolutionHeight(closest.tags.Resolution) {
+				closest = &candidates[i]
+			}
+		}
+		if closest != nil {
+			return closest.source.URL, closest.tags
+		}
+	}
+
+	// Fall back to "auto" quality (adaptive).
+	for _, c := range candidates {
+		if strings.EqualFold(c.source.Quality, "auto") {
+			return c.source.URL, c.tags
 		}
 	}
 
-	// Fall back to first available
-	return sources[0].URL
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if metadata.Score(c.tags) > metadata.Score(best.tags) {
+			best = c
+		}
+	}
+	return best.source.URL, best.tags
+}
+
+// parseResolutionPreference parses a preference like "1080" or "1080p"
+// into its height in pixels, reporting false if preferred isn't numeric
+// (e.g. "auto").
+func parseResolutionPreference(preferred string) (int, bool) {
+	h := resolutionHeight(preferred)
+	return h, h != 0
+}
+
+// resolutionHeight converts a height string like "1080p" or "1080" (a
+// metadata.Release.Resolution or a raw quality preference) into its
+// height in pixels, or 0 if unrecognized.
+func resolutionHeight(s string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(s, "p"))
+	if err != nil {
+		return 0
+	}
+	return n
 }
 
 // extractEmbedID extracts the ID portion from an embed URL.
@@ -1,86 +1,300 @@
-// Package download provides secure ffmpeg-based media downloading.
-// Uses exec.Command with explicit argument slices and validates
-// output paths against directory traversal attacks.
-package download
-
-import (
-	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-
-	"lobster/internal/httputil"
-	"lobster/internal/media"
-)
-
-// Download fetches a stream to a local file using ffmpeg.
-func Download(stream *media.Stream, title string, outputDir string, subFile string) (string, error) {
-	// Validate ffmpeg is available
-	ffmpegPath, err := exec.LookPath("ffmpeg")
-	if err != nil {
-		return "", fmt.Errorf("ffmpeg not found in PATH: %w", err)
-	}
-
-	// Create output directory if needed
-	absDir, err := filepath.Abs(outputDir)
-	if err != nil {
-		return "", fmt.Errorf("resolving output directory: %w", err)
-	}
-	if err := os.MkdirAll(absDir, 0755); err != nil {
-		return "", fmt.Errorf("creating output directory: %w", err)
-	}
-
-	// Sanitize filename and validate path
-	filename := httputil.SanitizeFilename(title) + ".mkv"
-	outputPath, err := httputil.SafeDownloadPath(absDir, filename)
-	if err != nil {
-		return "", fmt.Errorf("invalid output path: %w", err)
-	}
-
-	// Build ffmpeg args as explicit slice
-	args := []string{
-		"-y", // Overwrite output
-		"-i", stream.URL,
-	}
-
-	// Add subtitle if available
-	if subFile != "" {
-		args = append(args,
-			"-i", subFile,
-			"-c:s", "srt", // Convert subtitles to SRT for MKV
-		)
-	}
-
-	args = append(args,
-		"-c:v", "copy", // Copy video stream (no re-encoding)
-		"-c:a", "copy", // Copy audio stream
-	)
-
-	if subFile != "" {
-		args = append(args,
-			"-map", "0:v", // Video from first input
-			"-map", "0:a", // Audio from first input
-			"-map", "1:s", // Subtitles from second input
-		)
-	}
-
-	// Add metadata
-	args = append(args,
-		"-metadata", fmt.Sprintf("title=%s", title),
-		outputPath,
-	)
-
-	cmd := exec.Command(ffmpegPath, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	fmt.Fprintf(os.Stderr, "Downloading to: %s\n", outputPath)
-
-	if err := cmd.Run(); err != nil {
-		// Clean up partial download on failure
-		os.Remove(outputPath)
-		return "", fmt.Errorf("ffmpeg download failed: %w", err)
-	}
-
-	return outputPath, nil
-}
+// Package download provides secure media downloading: a single-shot
+// ffmpeg pipeline (the original behavior), or a segmented downloader that
+// fetches HLS segments / HTTP Range chunks in parallel and can resume an
+// interrupted download. Uses exec.Command with explicit argument slices
+// and validates output paths against directory traversal attacks.
+package download
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"lobster/internal/media"
+)
+
+// Options configures Download's concurrency and resume behavior. The zero
+// value (Concurrency <= 0) preserves the original single ffmpeg-call
+// behavior.
+type Options struct {
+	Concurrency int  // parallel segment/range fetchers when > 0
+	Resume      bool // resume from a .part.json sidecar instead of starting over
+	Retries     int  // per-segment/per-range transport retry count; <= 0 uses DefaultRetries
+
+	// NamingTemplate controls the output path, relative to outputDir,
+	// expanded by buildFilename (e.g. "{title}/{title} S{season:02}E{episode:02}.mkv").
+	// Empty falls back to LayoutFormat, then to DefaultNamingTemplate.
+	// Season/Episode are 0 for movies; Year is "" if unknown.
+	NamingTemplate string
+	Season         int
+	Episode        int
+	Year           string
+
+	// LayoutFormat selects a built-in Plex/Kodi-style directory layout
+	// (see layoutTemplates) when NamingTemplate is empty: "movie", "tv",
+	// or "anime". Unrecognized or empty values fall back to
+	// DefaultNamingTemplate, same as today's behavior.
+	LayoutFormat string
+
+	// Languages names the language of each entry in Download's subFiles,
+	// by index (e.g. subFiles[0] is Languages[0]'s track). Each name is
+	// mapped to an ISO 639-2 code via subtitle.LanguageCode for the muxed
+	// track's language metadata; an unmapped or missing entry just mutes
+	// that track's language tag rather than failing the download.
+	Languages []string
+}
+
+// DefaultConcurrency is used whenever a caller asks for segmented
+// downloading without specifying a worker count.
+const DefaultConcurrency = 4
+
+// DefaultRetries is used whenever Options.Retries is <= 0.
+const DefaultRetries = segmentFetchRetries
+
+// Download fetches a stream to a local file. With the zero Options it
+// behaves exactly as before: one ffmpeg invocation handles fetching,
+// remuxing, and subtitle embedding. With Options.Concurrency > 0, it
+// switches to a segmented downloader — parallel HLS segment fetches, or
+// parallel HTTP Range requests for a plain MP4/MKV source — and uses
+// ffmpeg only for the final remux/subtitle mux. Options.Resume picks up
+// an interrupted download from its .part.json sidecar instead of
+// restarting from scratch.
+func Download(stream *media.Stream, title string, outputDir string, subFiles []string, opts Options) (string, error) {
+	var (
+		outputPath string
+		err        error
+	)
+	if opts.Concurrency <= 0 {
+		outputPath, err = downloadSingleShot(stream, title, outputDir, subFiles, opts)
+	} else {
+		outputPath, err = downloadSegmented(stream, title, outputDir, subFiles, opts)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	runHooks(context.Background(), Result{
+		Path:    outputPath,
+		Title:   title,
+		Year:    opts.Year,
+		Season:  opts.Season,
+		Episode: opts.Episode,
+	})
+
+	return outputPath, nil
+}
+
+// downloadSingleShot is the original implementation: ffmpeg fetches,
+// remuxes, and (optionally) embeds subtitles in one invocation.
+func downloadSingleShot(stream *media.Stream, title string, outputDir string, subFiles []string, opts Options) (string, error) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg not found in PATH: %w", err)
+	}
+
+	absDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("resolving output directory: %w", err)
+	}
+	if err := os.MkdirAll(absDir, 0755); err != nil {
+		return "", fmt.Errorf("creating output directory: %w", err)
+	}
+
+	outputPath, err := resolveOutputPath(absDir, opts, title, ".mkv")
+	if err != nil {
+		return "", fmt.Errorf("invalid output path: %w", err)
+	}
+
+	args := []string{
+		"-y", // Overwrite output
+		"-i", stream.URL,
+	}
+
+	args = append(args, subtitleInputArgs(subFiles)...)
+
+	args = append(args,
+		"-c:v", "copy", // Copy video stream (no re-encoding)
+		"-c:a", "copy", // Copy audio stream
+	)
+
+	if len(subFiles) > 0 {
+		args = append(args,
+			"-map", "0:v", // Video from first input
+			"-map", "0:a", // Audio from first input
+		)
+	}
+	args = append(args, subtitleMapArgs(subFiles, opts.Languages, 1)...)
+
+	args = append(args,
+		"-metadata", fmt.Sprintf("title=%s", title),
+		outputPath,
+	)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	fmt.Fprintf(os.Stderr, "Downloading to: %s\n", outputPath)
+
+	if err := cmd.Run(); err != nil {
+		os.Remove(outputPath)
+		return "", fmt.Errorf("ffmpeg download failed: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// downloadSegmented fetches stream.URL in parallel pieces (HLS segments or
+// HTTP Range chunks) and only shells out to ffmpeg for the final mux.
+func downloadSegmented(stream *media.Stream, title, outputDir string, subFiles []string, opts Options) (string, error) {
+	absDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("resolving output directory: %w", err)
+	}
+	if err := os.MkdirAll(absDir, 0755); err != nil {
+		return "", fmt.Errorf("creating output directory: %w", err)
+	}
+
+	outputPath, err := resolveOutputPath(absDir, opts, title, ".mkv")
+	if err != nil {
+		return "", fmt.Errorf("invalid output path: %w", err)
+	}
+
+	ctx := context.Background()
+
+	if isHLSURL(stream.URL) {
+		fmt.Fprintf(os.Stderr, "Downloading (HLS, %d workers) to: %s\n", workerCount(opts), outputPath)
+		finalPath, err := downloadHLS(ctx, stream.URL, outputPath, subFiles, title, opts)
+		if err != nil {
+			return "", err
+		}
+		return finalPath, nil
+	}
+
+	if len(subFiles) > 0 {
+		if _, err := exec.LookPath("ffmpeg"); err != nil {
+			return "", fmt.Errorf("ffmpeg not found in PATH: needed to embed subtitles: %w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Downloading (ranged, %d workers) to: %s\n", workerCount(opts), outputPath)
+
+	tmpPath := outputPath + ".download.tmp"
+	if err := downloadRanged(ctx, stream.URL, tmpPath, opts); err != nil {
+		return "", err
+	}
+
+	if len(subFiles) == 0 {
+		if err := os.Rename(tmpPath, outputPath); err != nil {
+			return "", fmt.Errorf("finalizing download: %w", err)
+		}
+		return outputPath, nil
+	}
+
+	defer os.Remove(tmpPath)
+	if err := muxWithSubtitle(tmpPath, subFiles, opts.Languages, outputPath, title); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// resolveOutputPath expands opts' naming template against title and
+// opts.Season/Episode, then validates the result stays inside absDir.
+func resolveOutputPath(absDir string, opts Options, title, ext string) (string, error) {
+	template := opts.NamingTemplate
+	if template == "" {
+		template = layoutTemplates[opts.LayoutFormat]
+	}
+	rel := buildFilename(template, title, opts.Year, opts.Season, opts.Episode, ext)
+	return safeNestedPath(absDir, rel)
+}
+
+// safeNestedPath joins rel (already sanitized component-by-component by
+// buildFilename) onto absDir, creates any intermediate directories, and
+// rejects the result if it escapes absDir — the same containment check
+// httputil.SafeDownloadPath performs, extended to allow the subdirectories
+// a naming template like "{title}/{title} S{season:02}E{episode:02}.mkv"
+// produces (which SafeDownloadPath's single-component sanitizing would
+// otherwise collapse to a basename).
+func safeNestedPath(absDir, rel string) (string, error) {
+	full := filepath.Join(absDir, rel)
+
+	resolved, err := filepath.Abs(full)
+	if err != nil {
+		return "", fmt.Errorf("resolving path: %w", err)
+	}
+	if !strings.HasPrefix(resolved, absDir+string(filepath.Separator)) && resolved != absDir {
+		return "", fmt.Errorf("path traversal detected: %q escapes %q", resolved, absDir)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(resolved), 0755); err != nil {
+		return "", fmt.Errorf("creating output directory: %w", err)
+	}
+
+	return resolved, nil
+}
+
+func workerCount(opts Options) int {
+	if opts.Concurrency > 0 {
+		return opts.Concurrency
+	}
+	return DefaultConcurrency
+}
+
+// retryCount returns opts.Retries, falling back to DefaultRetries when
+// unset.
+func retryCount(opts Options) int {
+	if opts.Retries > 0 {
+		return opts.Retries
+	}
+	return DefaultRetries
+}
+
+// isHLSURL reports whether rawURL points at an HLS playlist, judging by
+// its path suffix (the same signal extractors use when deciding whether a
+// resolved source needs master-playlist parsing).
+func isHLSURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.Contains(strings.ToLower(rawURL), ".m3u8")
+	}
+	return strings.HasSuffix(strings.ToLower(u.Path), ".m3u8")
+}
+
+// muxWithSubtitle remuxes a single already-downloaded file, embedding
+// subFiles as SRT tracks, mirroring downloadSingleShot's subtitle step.
+func muxWithSubtitle(inputPath string, subFiles, languages []string, outputPath, title string) error {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found in PATH: %w", err)
+	}
+
+	args := []string{
+		"-y",
+		"-i", inputPath,
+	}
+	args = append(args, subtitleInputArgs(subFiles)...)
+	args = append(args,
+		"-c:v", "copy",
+		"-c:a", "copy",
+		"-map", "0:v",
+		"-map", "0:a",
+	)
+	args = append(args, subtitleMapArgs(subFiles, languages, 1)...)
+	args = append(args,
+		"-metadata", fmt.Sprintf("title=%s", title),
+		outputPath,
+	)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg mux failed: %w", err)
+	}
+	return nil
+}
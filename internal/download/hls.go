@@ -0,0 +1,508 @@
+package download
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lobster/internal/httputil"
+)
+
+// errEncryptedPlaylist is returned when a media playlist declares segment
+// encryption this downloader doesn't support — anything other than plain
+// METHOD=AES-128 (e.g. SAMPLE-AES, which encrypts inside the container
+// rather than the whole segment and needs a real demuxer to handle).
+var errEncryptedPlaylist = errors.New("playlist uses a segment encryption method other than AES-128, which the segmented downloader does not support; retry with --downloader=ffmpeg to let ffmpeg handle it")
+
+// hlsKey describes an #EXT-X-KEY in effect for one or more segments. A
+// playlist may rotate keys partway through (a new #EXT-X-KEY tag before a
+// later segment), so each segment carries a pointer to the key that was
+// current when it was listed rather than assuming one key for the whole
+// playlist.
+type hlsKey struct {
+	method string
+	uri    string
+	iv     []byte // nil means "derive from the segment's media sequence number"
+}
+
+// hlsSegment is one entry of a parsed media playlist: its resolved URL,
+// the encryption key in effect (nil if unencrypted), and its media
+// sequence number, which HLS uses as the default IV when #EXT-X-KEY omits
+// an explicit one.
+type hlsSegment struct {
+	url string
+	key *hlsKey
+	seq uint64
+}
+
+// segmentFetchRetries bounds the per-segment retry loop, on top of the
+// 429/5xx retries httputil.Do already performs — this layer additionally
+// covers transport-level failures (timeouts, connection resets) that Do
+// doesn't retry.
+const segmentFetchRetries = 3
+
+// newHLSClient builds the HTTP client downloadHLS and fetchSegmentList use
+// to talk to the playlist/segment/key URLs. Overridden in tests to inject
+// a fixture transport instead of hitting the network.
+var newHLSClient = func() *http.Client {
+	return httputil.NewClient(httputil.DefaultNetConfig())
+}
+
+// downloadHLS fetches every segment of the media playlist at
+// playlistURL in parallel (bounded by opts.Concurrency), writes them in
+// order to a temp directory, and muxes them into outputPath. It returns
+// the final output path, which differs from outputPath's extension when
+// ffmpeg isn't available (see muxSegments).
+func downloadHLS(ctx context.Context, playlistURL, outputPath string, subFiles []string, title string, opts Options) (string, error) {
+	segments, err := fetchSegmentList(ctx, playlistURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing media playlist: %w", err)
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("media playlist has no segments")
+	}
+
+	client := newHLSClient()
+	keys, err := fetchSegmentKeys(ctx, client, segments)
+	if err != nil {
+		return "", fmt.Errorf("fetching segment key: %w", err)
+	}
+
+	var st *partState
+	if opts.Resume {
+		st, _ = loadPartState(outputPath)
+	}
+	if st == nil || st.SourceURL != playlistURL || st.Kind != "hls" {
+		st = &partState{SourceURL: playlistURL, Kind: "hls"}
+	}
+
+	// segDir is deliberately NOT removed on an error return: savePartState
+	// below persists DoneSegments to outputPath+".part.json" as each
+	// segment finishes, and a --resume retry trusts that sidecar to skip
+	// re-fetching them. Deleting segDir here too would leave the sidecar
+	// pointing at .ts files that no longer exist, and muxSegments would
+	// later fail trying to open them. Only the success path at the bottom
+	// of this function removes it.
+	segDir := outputPath + ".segments"
+	if err := os.MkdirAll(segDir, 0755); err != nil {
+		return "", fmt.Errorf("creating segment directory: %w", err)
+	}
+
+	prog := newProgress("segments", int64(len(segments)))
+	prog.done = int64(len(st.DoneSegments))
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, workerCount(opts))
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for i, seg := range segments {
+		segPath := filepath.Join(segDir, fmt.Sprintf("seg-%06d.ts", i))
+		if st.hasSegment(i) {
+			// Trust the sidecar only if the segment file it claims is done
+			// is actually still on disk; otherwise re-fetch it.
+			if _, err := os.Stat(segPath); err == nil {
+				continue
+			}
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, seg hlsSegment, segPath string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fetchSegmentToFile(ctx, client, seg, keys, segPath, retryCount(opts)); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("segment %d: %w", i, err)
+				}
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			if !st.hasSegment(i) {
+				st.DoneSegments = append(st.DoneSegments, i)
+			}
+			_ = savePartState(outputPath, st)
+			mu.Unlock()
+
+			prog.add(1)
+		}(i, seg, segPath)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+
+	finalPath, err := muxSegments(segDir, len(segments), subFiles, opts.Languages, title, outputPath)
+	if err != nil {
+		return "", err
+	}
+
+	removePartState(outputPath)
+	os.RemoveAll(segDir)
+	return finalPath, nil
+}
+
+// fetchSegmentList downloads an HLS media playlist and parses it via
+// parseMediaPlaylist.
+func fetchSegmentList(ctx context.Context, playlistURL string) ([]hlsSegment, error) {
+	client := newHLSClient()
+	resp, err := httputil.GetCtx(ctx, client, playlistURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading playlist: %w", err)
+	}
+
+	return parseMediaPlaylist(data, playlistURL)
+}
+
+// parseMediaPlaylist parses an HLS media playlist's segment list, along
+// with the #EXT-X-KEY (if any) and media sequence number in effect for
+// each segment. Kept separate from fetchSegmentList so it's testable
+// without a network round trip, mirroring parseMasterPlaylist in
+// internal/extract.
+func parseMediaPlaylist(data []byte, playlistURL string) ([]hlsSegment, error) {
+	base, err := url.Parse(playlistURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing playlist URL: %w", err)
+	}
+
+	var segments []hlsSegment
+	var currentKey *hlsKey
+	var seq uint64
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE"):
+			if n, err := strconv.ParseUint(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"), 10, 64); err == nil {
+				seq = n
+			}
+			continue
+		case strings.HasPrefix(line, "#EXT-X-KEY"):
+			currentKey = parseKeyTag(line)
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+
+		segments = append(segments, hlsSegment{
+			url: resolveSegmentURI(base, line),
+			key: currentKey,
+			seq: seq,
+		})
+		seq++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading playlist: %w", err)
+	}
+
+	return segments, nil
+}
+
+// parseKeyTag parses a #EXT-X-KEY attribute line into an hlsKey, or nil
+// for METHOD=NONE (segments immediately following are unencrypted).
+func parseKeyTag(line string) *hlsKey {
+	attrs := parseAttributeList(strings.TrimPrefix(line, "#EXT-X-KEY:"))
+
+	method := attrs["METHOD"]
+	if method == "" || method == "NONE" {
+		return nil
+	}
+
+	key := &hlsKey{method: method, uri: attrs["URI"]}
+	if ivHex := strings.TrimPrefix(attrs["IV"], "0x"); ivHex != "" {
+		if iv, err := hex.DecodeString(ivHex); err == nil {
+			key.iv = iv
+		}
+	}
+	return key
+}
+
+// parseAttributeList parses an HLS tag's comma-separated KEY=VALUE (or
+// KEY="VALUE") attribute list, e.g. `METHOD=AES-128,URI="key.bin",IV=0x...`.
+func parseAttributeList(s string) map[string]string {
+	attrs := make(map[string]string)
+	for _, part := range splitAttributes(s) {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		attrs[strings.TrimSpace(kv[0])] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+	}
+	return attrs
+}
+
+// splitAttributes splits on commas that aren't inside a quoted string,
+// since a quoted URI attribute can itself contain commas.
+func splitAttributes(s string) []string {
+	var parts []string
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case r == ',' && !inQuotes:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		parts = append(parts, cur.String())
+	}
+	return parts
+}
+
+// resolveSegmentURI resolves a segment URI against the playlist's own
+// URL, the same way a browser or ffmpeg would for a relative segment
+// path.
+func resolveSegmentURI(base *url.URL, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// fetchSegmentKeys fetches the key bytes for every distinct #EXT-X-KEY URI
+// referenced by segments, once each, keyed by URI. Keys are small (16
+// bytes) so holding them all in memory for the download's duration is
+// fine.
+func fetchSegmentKeys(ctx context.Context, client *http.Client, segments []hlsSegment) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, seg := range segments {
+		if seg.key == nil {
+			continue
+		}
+		if seg.key.method != "AES-128" {
+			return nil, errEncryptedPlaylist
+		}
+		if _, ok := keys[seg.key.uri]; ok {
+			continue
+		}
+		resp, err := httputil.GetCtx(ctx, client, seg.key.uri)
+		if err != nil {
+			return nil, err
+		}
+		keyBytes, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(keyBytes) != aes.BlockSize {
+			return nil, fmt.Errorf("key %q: expected %d bytes, got %d", seg.key.uri, aes.BlockSize, len(keyBytes))
+		}
+		keys[seg.key.uri] = keyBytes
+	}
+	return keys, nil
+}
+
+// segmentIV returns the 16-byte IV for seg: the explicit #EXT-X-KEY IV if
+// one was given, otherwise its media sequence number as a big-endian
+// 128-bit integer, per the HLS spec's default.
+func segmentIV(seg hlsSegment) []byte {
+	if len(seg.key.iv) == aes.BlockSize {
+		return seg.key.iv
+	}
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], seg.seq)
+	return iv
+}
+
+// decryptSegment reverses AES-128-CBC + PKCS7 padding, the only HLS
+// segment encryption method this downloader supports.
+func decryptSegment(data, key, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("encrypted segment size %d is not a multiple of the block size", len(data))
+	}
+
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+
+	padLen := int(out[len(out)-1])
+	if padLen <= 0 || padLen > aes.BlockSize || padLen > len(out) {
+		return nil, fmt.Errorf("invalid PKCS7 padding")
+	}
+	return out[:len(out)-padLen], nil
+}
+
+// fetchSegmentToFile downloads seg to segPath, decrypting it first if it's
+// AES-128 encrypted, retrying transport-level failures (timeouts, resets)
+// a few times with a short backoff — the 429/5xx retries in httputil.Do
+// cover server-side failures, this covers the rest.
+func fetchSegmentToFile(ctx context.Context, client *http.Client, seg hlsSegment, keys map[string][]byte, segPath string, retries int) error {
+	var lastErr error
+	for attempt := 0; attempt < retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+			}
+		}
+
+		if err := fetchOnce(ctx, client, seg, keys, segPath); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
+
+func fetchOnce(ctx context.Context, client *http.Client, seg hlsSegment, keys map[string][]byte, path string) error {
+	resp, err := httputil.GetCtx(ctx, client, seg.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if seg.key != nil {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		plain, err := decryptSegment(data, keys[seg.key.uri], segmentIV(seg))
+		if err != nil {
+			return fmt.Errorf("decrypting segment: %w", err)
+		}
+		return os.WriteFile(path, plain, 0644)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// muxSegments concatenates the downloaded .ts segments (in order) and
+// remuxes them into outputPath with ffmpeg, embedding subFiles if given.
+// If ffmpeg isn't installed, it falls back to a raw concatenation of the
+// MPEG-TS segments (valid without a demuxer, since TS is designed to be
+// joined this way) written to outputPath with its extension swapped to
+// ".ts"; subtitle embedding is skipped in that case since muxing needs
+// ffmpeg. It returns the path actually written, which callers must use
+// instead of assuming outputPath.
+func muxSegments(segDir string, count int, subFiles, languages []string, title, outputPath string) (string, error) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return concatSegmentsRaw(segDir, count, outputPath)
+	}
+
+	listPath := filepath.Join(segDir, "concat.txt")
+	var sb strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&sb, "file '%s'\n", filepath.Join(segDir, fmt.Sprintf("seg-%06d.ts", i)))
+	}
+	if err := os.WriteFile(listPath, []byte(sb.String()), 0644); err != nil {
+		return "", fmt.Errorf("writing concat list: %w", err)
+	}
+
+	args := []string{
+		"-y",
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listPath,
+	}
+
+	args = append(args, subtitleInputArgs(subFiles)...)
+
+	args = append(args, "-c:v", "copy", "-c:a", "copy")
+
+	if len(subFiles) > 0 {
+		args = append(args, "-map", "0:v", "-map", "0:a")
+	}
+	args = append(args, subtitleMapArgs(subFiles, languages, 1)...)
+
+	args = append(args, "-metadata", fmt.Sprintf("title=%s", title), outputPath)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("ffmpeg mux failed: %w", err)
+	}
+	return outputPath, nil
+}
+
+// concatSegmentsRaw joins the downloaded .ts segments byte-for-byte into
+// a single file, without ffmpeg. It writes to outputPath with its
+// extension replaced by ".ts" (an MKV container needs ffmpeg to build; a
+// concatenated MPEG-TS stream doesn't).
+func concatSegmentsRaw(segDir string, count int, outputPath string) (string, error) {
+	tsPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".ts"
+
+	out, err := os.Create(tsPath)
+	if err != nil {
+		return "", fmt.Errorf("creating output file: %w", err)
+	}
+	defer out.Close()
+
+	for i := 0; i < count; i++ {
+		segPath := filepath.Join(segDir, fmt.Sprintf("seg-%06d.ts", i))
+		f, err := os.Open(segPath)
+		if err != nil {
+			return "", fmt.Errorf("reading segment %d: %w", i, err)
+		}
+		_, err = io.Copy(out, f)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("writing segment %d: %w", i, err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "ffmpeg not found; wrote raw MPEG-TS stream to %s (subtitles not embedded)\n", tsPath)
+	return tsPath, nil
+}
@@ -0,0 +1,46 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Result is what a finished Download hands to each registered Hook.
+// Season/Episode are 0 for movies; Year is "" if unknown.
+type Result struct {
+	Path    string
+	Title   string
+	Year    string
+	Season  int
+	Episode int
+}
+
+// Hook runs some side effect after a successful Download, e.g. triggering
+// a Kodi/Plex library rescan or notifying a webhook. Run's error is
+// logged, not propagated — a failed rescan shouldn't make an otherwise
+// successful download fail.
+type Hook interface {
+	Run(ctx context.Context, result Result) error
+}
+
+// hooks is the process-wide list invoked by every successful Download, in
+// registration order. Populated by RegisterConfiguredHooks from
+// ~/.config/lobster/hooks.toml, and by RegisterHook for Go-level callers
+// (e.g. a custom build embedding lobster as a library).
+var hooks []Hook
+
+// RegisterHook adds a Hook to run after every successful Download.
+func RegisterHook(h Hook) {
+	hooks = append(hooks, h)
+}
+
+// runHooks invokes every registered hook with result. A hook's failure is
+// reported to stderr and otherwise ignored, matching Run's doc comment.
+func runHooks(ctx context.Context, result Result) {
+	for _, h := range hooks {
+		if err := h.Run(ctx, result); err != nil {
+			fmt.Fprintf(os.Stderr, "post-download hook failed: %v\n", err)
+		}
+	}
+}
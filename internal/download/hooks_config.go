@@ -0,0 +1,252 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"lobster/internal/config"
+)
+
+// HooksConfig is the shape of ~/.config/lobster/hooks.toml. Each section is
+// an array of tables so a user can point more than one Kodi/Plex host (or
+// more than one webhook) at the same download, e.g.:
+//
+//	[[kodi]]
+//	host = "http://192.168.1.50:8080"
+//
+//	[[exec]]
+//	command = "/usr/local/bin/notify"
+//	args = ["{title}", "{path}"]
+type HooksConfig struct {
+	Kodi    []KodiHookConfig    `toml:"kodi"`
+	Plex    []PlexHookConfig    `toml:"plex"`
+	Exec    []ExecHookConfig    `toml:"exec"`
+	Webhook []WebhookHookConfig `toml:"webhook"`
+}
+
+// KodiHookConfig points at one Kodi instance's JSON-RPC endpoint.
+type KodiHookConfig struct {
+	Host     string `toml:"host"` // e.g. "http://192.168.1.50:8080"
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+}
+
+// PlexHookConfig points at one Plex Media Server library section.
+type PlexHookConfig struct {
+	Host      string `toml:"host"` // e.g. "http://192.168.1.50:32400"
+	Token     string `toml:"token"`
+	SectionID string `toml:"section_id"`
+}
+
+// ExecHookConfig runs Command with Args after a successful download. Each
+// Args entry may contain {path}, {title}, and {year} placeholders, which
+// are substituted per-argument (never joined into a shell string) so a
+// title containing shell metacharacters can't escape into the command.
+type ExecHookConfig struct {
+	Command string   `toml:"command"`
+	Args    []string `toml:"args"`
+}
+
+// WebhookHookConfig POSTs a small JSON body describing the download to URL.
+type WebhookHookConfig struct {
+	URL string `toml:"url"`
+}
+
+// LoadHooksConfig reads ~/.config/lobster/hooks.toml. A missing file
+// returns an empty (all-disabled) HooksConfig, matching config.Load's
+// behavior for the main config file.
+func LoadHooksConfig() (*HooksConfig, error) {
+	hc := &HooksConfig{}
+
+	path, err := config.HooksConfigPath()
+	if err != nil {
+		return hc, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hc, nil
+		}
+		return nil, fmt.Errorf("reading hooks config: %w", err)
+	}
+
+	if err := toml.Unmarshal(data, hc); err != nil {
+		return nil, fmt.Errorf("parsing hooks config %s: %w", path, err)
+	}
+
+	return hc, nil
+}
+
+// RegisterConfiguredHooks builds a Hook for each entry in hc and adds it
+// via RegisterHook.
+func RegisterConfiguredHooks(hc *HooksConfig) {
+	for _, k := range hc.Kodi {
+		RegisterHook(kodiHook(k))
+	}
+	for _, p := range hc.Plex {
+		RegisterHook(plexHook(p))
+	}
+	for _, e := range hc.Exec {
+		RegisterHook(execHook(e))
+	}
+	for _, w := range hc.Webhook {
+		RegisterHook(webhookHook(w))
+	}
+}
+
+// hookHTTPClient is shared by the Kodi/Plex/webhook hooks below. These
+// hit either a LAN device (Kodi/Plex, often plain HTTP) or a
+// user-specified URL, so unlike httputil.ValidateURL (used for the
+// scraping/extractor paths) they don't require HTTPS.
+var hookHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+type kodiHook KodiHookConfig
+
+// Run asks Kodi to rescan its video library via JSON-RPC.
+func (h kodiHook) Run(ctx context.Context, result Result) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "VideoLibrary.Scan",
+		"id":      1,
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimRight(h.Host, "/") + "/jsonrpc"
+	if _, err := url.Parse(endpoint); err != nil {
+		return fmt.Errorf("invalid kodi host %q: %w", h.Host, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.Username != "" {
+		req.SetBasicAuth(h.Username, h.Password)
+	}
+
+	resp, err := hookHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("kodi rescan request to %s: %w", h.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("kodi rescan request to %s: status %d", h.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+type plexHook PlexHookConfig
+
+// Run asks Plex to refresh the configured library section.
+func (h plexHook) Run(ctx context.Context, result Result) error {
+	endpoint := fmt.Sprintf("%s/library/sections/%s/refresh?X-Plex-Token=%s",
+		strings.TrimRight(h.Host, "/"), url.PathEscape(h.SectionID), url.QueryEscape(h.Token))
+	if _, err := url.Parse(endpoint); err != nil {
+		return fmt.Errorf("invalid plex host %q: %w", h.Host, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := hookHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("plex refresh request to %s: %w", h.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("plex refresh request to %s: status %d", h.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+type execHook ExecHookConfig
+
+// substituteHookArgs expands {path}/{title}/{year} in each of args
+// separately, so a value containing shell metacharacters can't escape
+// into the command — there is no intermediate shell string to escape
+// into.
+func substituteHookArgs(args []string, result Result) []string {
+	replacer := strings.NewReplacer(
+		"{path}", result.Path,
+		"{title}", result.Title,
+		"{year}", result.Year,
+	)
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = replacer.Replace(a)
+	}
+	return out
+}
+
+// Run substitutes {path}/{title}/{year} into each argument separately and
+// runs Command directly (never through a shell).
+func (h execHook) Run(ctx context.Context, result Result) error {
+	if h.Command == "" {
+		return fmt.Errorf("exec hook has no command configured")
+	}
+
+	args := substituteHookArgs(h.Args, result)
+
+	cmd := exec.CommandContext(ctx, h.Command, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("exec hook %q: %w", h.Command, err)
+	}
+	return nil
+}
+
+type webhookHook WebhookHookConfig
+
+// Run POSTs a small JSON body describing the completed download.
+func (h webhookHook) Run(ctx context.Context, result Result) error {
+	if _, err := url.Parse(h.URL); err != nil {
+		return fmt.Errorf("invalid webhook url %q: %w", h.URL, err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"path":    result.Path,
+		"title":   result.Title,
+		"year":    result.Year,
+		"season":  result.Season,
+		"episode": result.Episode,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hookHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request to %s: %w", h.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("webhook request to %s: status %d", h.URL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package download
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestSubstituteHookArgs(t *testing.T) {
+	result := Result{Path: "/videos/Movie (2024).mkv", Title: "Movie; rm -rf /", Year: "2024"}
+	args := substituteHookArgs([]string{"{title}", "{year}", "{path}", "--flag"}, result)
+
+	want := []string{"Movie; rm -rf /", "2024", "/videos/Movie (2024).mkv", "--flag"}
+	for i, w := range want {
+		if args[i] != w {
+			t.Errorf("args[%d] = %q, want %q", i, args[i], w)
+		}
+	}
+}
+
+func TestExecHookRun(t *testing.T) {
+	h := execHook(ExecHookConfig{Command: "true"})
+	if err := h.Run(context.Background(), Result{}); err != nil {
+		t.Errorf("Run() with the `true` command should succeed, got: %v", err)
+	}
+}
+
+func TestRegisterHookRunsInOrder(t *testing.T) {
+	prev := hooks
+	defer func() { hooks = prev }()
+	hooks = nil
+
+	var order []string
+	RegisterHook(fakeHook{name: "first", order: &order})
+	RegisterHook(fakeHook{name: "second", order: &order})
+
+	runHooks(context.Background(), Result{Path: "/videos/Movie.mkv"})
+
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("runHooks order = %v, want [first second]", order)
+	}
+}
+
+type fakeHook struct {
+	name  string
+	order *[]string
+}
+
+func (h fakeHook) Run(ctx context.Context, result Result) error {
+	*h.order = append(*h.order, h.name)
+	return nil
+}
+
+func TestLoadHooksConfigParsesSections(t *testing.T) {
+	var hc HooksConfig
+	data := `
+[[kodi]]
+host = "http://192.168.1.50:8080"
+
+[[plex]]
+host = "http://192.168.1.50:32400"
+token = "abc123"
+section_id = "1"
+
+[[exec]]
+command = "/usr/local/bin/notify"
+args = ["{title}", "{path}"]
+
+[[webhook]]
+url = "https://example.com/hook"
+`
+	if err := toml.Unmarshal([]byte(data), &hc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if len(hc.Kodi) != 1 || hc.Kodi[0].Host != "http://192.168.1.50:8080" {
+		t.Errorf("Kodi = %+v", hc.Kodi)
+	}
+	if len(hc.Plex) != 1 || hc.Plex[0].SectionID != "1" {
+		t.Errorf("Plex = %+v", hc.Plex)
+	}
+	if len(hc.Exec) != 1 || len(hc.Exec[0].Args) != 2 {
+		t.Errorf("Exec = %+v", hc.Exec)
+	}
+	if len(hc.Webhook) != 1 || hc.Webhook[0].URL != "https://example.com/hook" {
+		t.Errorf("Webhook = %+v", hc.Webhook)
+	}
+}
@@ -0,0 +1,92 @@
+package download
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lobster/internal/httputil"
+)
+
+// DefaultNamingTemplate is used whenever Options.NamingTemplate is empty
+// and Options.LayoutFormat doesn't match a known layoutTemplates preset.
+// It mirrors the old hardcoded "<title>.mkv" behavior for movies, and
+// additionally supports {season}/{episode} tokens for TV downloads.
+const DefaultNamingTemplate = "{title}"
+
+// layoutTemplates are the built-in Plex/Kodi-style directory layouts
+// selectable via Options.LayoutFormat when NamingTemplate is empty. An
+// unrecognized LayoutFormat looks up to "", which buildFilename treats
+// the same as an empty NamingTemplate: DefaultNamingTemplate.
+var layoutTemplates = map[string]string{
+	"movie": "Movies/{title} ({year})/{title} ({year})",
+	"tv":     "TV Shows/{title}/Season {season:02}/{title} - S{season:02}E{episode:02}",
+	"anime":  "Anime/{title}/{title} - {episode:03}",
+}
+
+// buildFilename expands template's {title}, {year}, {season}, and
+// {episode} tokens into a sanitized relative path, then appends ext. A
+// numeric token may carry a zero-padding width, e.g. "{season:02}". Movie
+// downloads simply pass season/episode as 0 and use a template that
+// doesn't reference them; year is "" when unknown.
+//
+// The expanded path is sanitized component-by-component so a title or
+// template containing path separators can't escape the download
+// directory (the same risk httputil.SanitizeFilename already guards
+// against for the simple case).
+func buildFilename(template, title, year string, season, episode int, ext string) string {
+	if template == "" {
+		template = DefaultNamingTemplate
+	}
+
+	expanded := expandToken(template, "title", title)
+	expanded = expandToken(expanded, "year", year)
+	expanded = expandNumericToken(expanded, "season", season)
+	expanded = expandNumericToken(expanded, "episode", episode)
+
+	parts := strings.Split(filepathSlashes(expanded), "/")
+	for i, p := range parts {
+		parts[i] = httputil.SanitizeFilename(p)
+	}
+	return strings.Join(parts, string('/')) + ext
+}
+
+// filepathSlashes normalizes backslashes to forward slashes so the
+// template can be written with either separator.
+func filepathSlashes(s string) string {
+	return strings.ReplaceAll(s, "\\", "/")
+}
+
+func expandToken(template, name, value string) string {
+	return strings.ReplaceAll(template, "{"+name+"}", value)
+}
+
+// expandNumericToken replaces {name} and {name:0N} with value, zero-padded
+// to N digits for the latter form.
+func expandNumericToken(template, name string, value int) string {
+	plain := "{" + name + "}"
+	template = strings.ReplaceAll(template, plain, strconv.Itoa(value))
+
+	prefix := "{" + name + ":0"
+	for {
+		start := strings.Index(template, prefix)
+		if start == -1 {
+			break
+		}
+		end := strings.Index(template[start:], "}")
+		if end == -1 {
+			break
+		}
+		end += start
+
+		widthStr := template[start+len(prefix) : end]
+		width, err := strconv.Atoi(widthStr)
+		if err != nil {
+			break
+		}
+
+		formatted := fmt.Sprintf("%0*d", width, value)
+		template = template[:start] + formatted + template[end+1:]
+	}
+	return template
+}
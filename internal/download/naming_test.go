@@ -0,0 +1,43 @@
+package download
+
+import "testing"
+
+func TestBuildFilenameMovie(t *testing.T) {
+	got := buildFilename("", "The Matrix", "", 0, 0, ".mkv")
+	want := "The Matrix.mkv"
+	if got != want {
+		t.Errorf("buildFilename() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFilenameEpisode(t *testing.T) {
+	tmpl := "{title}/{title} S{season:02}E{episode:02}"
+	got := buildFilename(tmpl, "Breaking Bad", "", 1, 7, ".mkv")
+	want := "Breaking Bad/Breaking Bad S01E07.mkv"
+	if got != want {
+		t.Errorf("buildFilename() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFilenameSanitizesComponents(t *testing.T) {
+	got := buildFilename("{title}", "Who: Am I?", "", 0, 0, ".mkv")
+	if got == "Who: Am I?.mkv" {
+		t.Errorf("buildFilename() did not sanitize path-unsafe characters: %q", got)
+	}
+}
+
+func TestBuildFilenameMovieLayout(t *testing.T) {
+	got := buildFilename(layoutTemplates["movie"], "The Matrix", "1999", 0, 0, ".mkv")
+	want := "Movies/The Matrix (1999)/The Matrix (1999).mkv"
+	if got != want {
+		t.Errorf("buildFilename() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFilenameTVLayout(t *testing.T) {
+	got := buildFilename(layoutTemplates["tv"], "Breaking Bad", "", 1, 7, ".mkv")
+	want := "TV Shows/Breaking Bad/Season 01/Breaking Bad - S01E07.mkv"
+	if got != want {
+		t.Errorf("buildFilename() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package download
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// partState is the on-disk sidecar for a download in progress, written
+// next to the output file as "<outputPath>.part.json". It lets --resume
+// skip work that's already on disk instead of restarting from scratch.
+type partState struct {
+	SourceURL string `json:"source_url"`
+	Kind      string `json:"kind"` // "hls" or "range"
+
+	// HLS: indexes into the media playlist's segment list that have
+	// already been fetched into the segment temp dir.
+	DoneSegments []int `json:"done_segments,omitempty"`
+
+	// Range: byte ranges [start, end] (inclusive) already written to the
+	// output file.
+	TotalBytes int64      `json:"total_bytes,omitempty"`
+	DoneRanges [][2]int64 `json:"done_ranges,omitempty"`
+}
+
+func partPath(outputPath string) string {
+	return outputPath + ".part.json"
+}
+
+func loadPartState(outputPath string) (*partState, error) {
+	data, err := os.ReadFile(partPath(outputPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var st partState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// savePartState writes st atomically (temp file + rename), matching the
+// pattern history.Save and cache.Store use for their own state files.
+func savePartState(outputPath string, st *partState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p := partPath(outputPath)
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func removePartState(outputPath string) {
+	os.Remove(partPath(outputPath))
+}
+
+func (st *partState) hasSegment(i int) bool {
+	for _, d := range st.DoneSegments {
+		if d == i {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *partState) hasRange(start, end int64) bool {
+	for _, r := range st.DoneRanges {
+		if r[0] == start && r[1] == end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package download
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPartStateSaveLoadRoundTrip(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "movie.mkv")
+
+	st := &partState{
+		SourceURL:    "https://cdn.example.com/playlist.m3u8",
+		Kind:         "hls",
+		DoneSegments: []int{0, 2, 3},
+	}
+	if err := savePartState(outputPath, st); err != nil {
+		t.Fatalf("savePartState: %v", err)
+	}
+
+	loaded, err := loadPartState(outputPath)
+	if err != nil {
+		t.Fatalf("loadPartState: %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("loadPartState returned nil after a successful save")
+	}
+	if loaded.SourceURL != st.SourceURL || loaded.Kind != st.Kind {
+		t.Errorf("loaded = %+v, want matching SourceURL/Kind from %+v", loaded, st)
+	}
+
+	for _, done := range []int{0, 2, 3} {
+		if !loaded.hasSegment(done) {
+			t.Errorf("hasSegment(%d) = false, want true after resume load", done)
+		}
+	}
+	if loaded.hasSegment(1) {
+		t.Error("hasSegment(1) = true, want false (never marked done)")
+	}
+
+	removePartState(outputPath)
+	if st, err := loadPartState(outputPath); err != nil || st != nil {
+		t.Errorf("loadPartState after removePartState = (%+v, %v), want (nil, nil)", st, err)
+	}
+}
+
+func TestPartStateMissingFileReturnsNil(t *testing.T) {
+	st, err := loadPartState(filepath.Join(t.TempDir(), "never-downloaded.mkv"))
+	if err != nil {
+		t.Fatalf("loadPartState on a missing sidecar should not error, got: %v", err)
+	}
+	if st != nil {
+		t.Errorf("loadPartState on a missing sidecar = %+v, want nil", st)
+	}
+}
+
+func TestPartStateHasRange(t *testing.T) {
+	st := &partState{DoneRanges: [][2]int64{{0, 1023}, {2048, 3071}}}
+
+	if !st.hasRange(0, 1023) {
+		t.Error("hasRange(0, 1023) = false, want true")
+	}
+	if !st.hasRange(2048, 3071) {
+		t.Error("hasRange(2048, 3071) = false, want true")
+	}
+	if st.hasRange(1024, 2047) {
+		t.Error("hasRange(1024, 2047) = true, want false (gap between recorded ranges)")
+	}
+}
@@ -0,0 +1,57 @@
+package download
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// progress reports download progress to stderr with an ETA, throttled so
+// concurrent workers don't flood the terminal with one line per chunk.
+type progress struct {
+	mu        sync.Mutex
+	done      int64
+	total     int64
+	start     time.Time
+	lastPrint time.Time
+	label     string
+}
+
+func newProgress(label string, total int64) *progress {
+	return &progress{
+		label: label,
+		total: total,
+		start: time.Now(),
+	}
+}
+
+// add records n additional bytes/units completed and prints an updated
+// line at most a few times a second.
+func (p *progress) add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += n
+
+	now := time.Now()
+	if now.Sub(p.lastPrint) < 250*time.Millisecond && p.done < p.total {
+		return
+	}
+	p.lastPrint = now
+
+	elapsed := now.Sub(p.start)
+	var pct float64
+	var eta time.Duration
+	if p.total > 0 {
+		pct = float64(p.done) / float64(p.total) * 100
+		if p.done > 0 {
+			eta = time.Duration(float64(elapsed) * (float64(p.total-p.done) / float64(p.done)))
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "\r%s: %.1f%% (eta %s)   ", p.label, pct, eta.Round(time.Second))
+	if p.done >= p.total {
+		fmt.Fprintln(os.Stderr)
+	}
+}
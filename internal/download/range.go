@@ -0,0 +1,265 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lobster/internal/httputil"
+)
+
+// downloadRanged fetches sourceURL into outputPath, splitting it into
+// opts.Concurrency byte ranges fetched in parallel when the server
+// supports Range requests, falling back to a plain serial download
+// otherwise.
+func downloadRanged(ctx context.Context, sourceURL, outputPath string, opts Options) error {
+	client := httputil.NewClient(httputil.DefaultNetConfig())
+
+	size, rangeOK, err := probeRangeSupport(ctx, client, sourceURL)
+	if err != nil {
+		return fmt.Errorf("probing %s: %w", sourceURL, err)
+	}
+	if !rangeOK || size <= 0 {
+		return downloadSerial(ctx, client, sourceURL, outputPath)
+	}
+
+	var st *partState
+	if opts.Resume {
+		st, _ = loadPartState(outputPath)
+	}
+	if st == nil || st.SourceURL != sourceURL || st.Kind != "range" {
+		st = &partState{SourceURL: sourceURL, Kind: "range", TotalBytes: size}
+	}
+
+	f, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return fmt.Errorf("allocating output file: %w", err)
+	}
+	defer f.Close()
+
+	ranges := splitRanges(size, workerCount(opts))
+	prog := newProgress("bytes", size)
+	for _, r := range st.DoneRanges {
+		prog.done += r[1] - r[0] + 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, workerCount(opts))
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for _, r := range ranges {
+		if st.hasRange(r[0], r[1]) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(start, end int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			w := &offsetWriter{f: f, offset: start}
+			n, err := fetchRangeToFile(ctx, client, sourceURL, start, end, w, retryCount(opts))
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("range %d-%d: %w", start, end, err)
+				}
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			st.DoneRanges = append(st.DoneRanges, [2]int64{start, end})
+			_ = savePartState(outputPath, st)
+			mu.Unlock()
+
+			prog.add(n)
+		}(r[0], r[1])
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	removePartState(outputPath)
+	return nil
+}
+
+// probeRangeSupport checks whether the server honors Range requests by
+// asking for the first byte and inspecting the response. It returns the
+// full content size parsed from the Content-Range header.
+func probeRangeSupport(ctx context.Context, client *http.Client, rawURL string) (size int64, ok bool, err error) {
+	if err := httputil.ValidateURL(rawURL); err != nil {
+		return 0, false, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/121.0")
+
+	resp, err := httputil.Do(ctx, client, req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, false, nil
+	}
+
+	cr := resp.Header.Get("Content-Range")
+	idx := strings.LastIndex(cr, "/")
+	if idx == -1 || idx+1 >= len(cr) {
+		return 0, false, nil
+	}
+	size, err = strconv.ParseInt(cr[idx+1:], 10, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+
+	return size, true, nil
+}
+
+// splitRanges divides [0, size) into workers-many roughly equal,
+// inclusive byte ranges.
+func splitRanges(size int64, workers int) [][2]int64 {
+	if workers < 1 {
+		workers = 1
+	}
+	chunk := size / int64(workers)
+	if chunk == 0 {
+		return [][2]int64{{0, size - 1}}
+	}
+
+	ranges := make([][2]int64, 0, workers)
+	start := int64(0)
+	for i := 0; i < workers; i++ {
+		end := start + chunk - 1
+		if i == workers-1 || end >= size-1 {
+			end = size - 1
+		}
+		ranges = append(ranges, [2]int64{start, end})
+		start = end + 1
+		if start >= size {
+			break
+		}
+	}
+	return ranges
+}
+
+// offsetWriter implements io.Writer by writing at a fixed, advancing
+// offset into f, so each range worker can stream straight to its slice of
+// the output file without buffering the whole chunk in memory.
+type offsetWriter struct {
+	f      *os.File
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.f.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
+
+// fetchRangeToFile downloads [start, end] of rawURL into w, retrying
+// transport-level failures a few times.
+func fetchRangeToFile(ctx context.Context, client *http.Client, rawURL string, start, end int64, w io.Writer, retries int) (int64, error) {
+	var lastErr error
+	for attempt := 0; attempt < retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+			}
+		}
+
+		n, err := fetchRangeOnce(ctx, client, rawURL, start, end, w)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return n, nil
+	}
+	return 0, lastErr
+}
+
+func fetchRangeOnce(ctx context.Context, client *http.Client, rawURL string, start, end int64, w io.Writer) (int64, error) {
+	if err := httputil.ValidateURL(rawURL); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/121.0")
+
+	resp, err := httputil.Do(ctx, client, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.Copy(w, resp.Body)
+}
+
+// downloadSerial fetches the whole file in one request, for servers that
+// don't support Range requests.
+func downloadSerial(ctx context.Context, client *http.Client, rawURL, outputPath string) error {
+	resp, err := httputil.GetCtx(ctx, client, rawURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	prog := newProgress("bytes", resp.ContentLength)
+	_, err = io.Copy(io.MultiWriter(f, progressWriter{prog}), resp.Body)
+	return err
+}
+
+// progressWriter feeds bytes written through it into a progress tracker,
+// so downloadSerial can report percent/ETA the same way the parallel path
+// does.
+type progressWriter struct {
+	p *progress
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	w.p.add(int64(len(p)))
+	return len(p), nil
+}
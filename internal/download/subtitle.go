@@ -0,0 +1,56 @@
+package download
+
+import (
+	"fmt"
+
+	"lobster/internal/subtitle"
+)
+
+// subtitleInputArgs builds the ffmpeg "-i <file> -c:s srt" fragment for
+// each of subFiles, converting every track to SRT for MKV output. Callers
+// append this right after their primary input(s), before -map/-c:v/-c:a.
+func subtitleInputArgs(subFiles []string) []string {
+	var args []string
+	for _, f := range subFiles {
+		args = append(args, "-i", f, "-c:s", "srt")
+	}
+	return args
+}
+
+// subtitleMapArgs builds the "-map N:s" / "-metadata:s:s:N language=..." /
+// "-disposition:s:0 default" fragment for subFiles, where
+// inputIndexOffset is the ffmpeg input index subFiles[0] occupies (i.e.
+// the count of inputs already added ahead of it: 1 for a single primary
+// input, 2 for a concat-list input plus the primary, etc.). Callers append
+// this after their own video/audio -map flags, so subtitle streams land
+// after video/audio in output stream order, matching the single-subtitle
+// behavior this replaces.
+//
+// languages pairs with subFiles by index (languages[i] names subFiles[i]'s
+// language, e.g. "English") and is optional: a missing entry, or one that
+// subtitle.LanguageCode doesn't recognize, simply omits that track's
+// language metadata line rather than aborting the mux — the track is
+// still embedded, just without a language tag for the player to show.
+func subtitleMapArgs(subFiles, languages []string, inputIndexOffset int) []string {
+	if len(subFiles) == 0 {
+		return nil
+	}
+
+	var args []string
+	for i := range subFiles {
+		args = append(args, "-map", fmt.Sprintf("%d:s", inputIndexOffset+i))
+	}
+	for i := range subFiles {
+		if i >= len(languages) {
+			continue
+		}
+		code, ok := subtitle.LanguageCode(languages[i])
+		if !ok {
+			continue
+		}
+		args = append(args, fmt.Sprintf("-metadata:s:s:%d", i), fmt.Sprintf("language=%s", code))
+	}
+	args = append(args, "-disposition:s:0", "default")
+
+	return args
+}
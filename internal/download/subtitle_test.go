@@ -0,0 +1,51 @@
+package download
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubtitleMapArgsOnePerLanguage(t *testing.T) {
+	subFiles := []string{"en.srt", "fr.srt"}
+	languages := []string{"English", "French"}
+
+	inputs := subtitleInputArgs(subFiles)
+	maps := subtitleMapArgs(subFiles, languages, 1)
+
+	wantInputs := "-i en.srt -c:s srt -i fr.srt -c:s srt"
+	if got := strings.Join(inputs, " "); got != wantInputs {
+		t.Errorf("subtitleInputArgs = %q, want %q", got, wantInputs)
+	}
+
+	wantMaps := "-map 1:s -map 2:s -metadata:s:s:0 language=eng -metadata:s:s:1 language=fre -disposition:s:0 default"
+	if got := strings.Join(maps, " "); got != wantMaps {
+		t.Errorf("subtitleMapArgs = %q, want %q", got, wantMaps)
+	}
+}
+
+func TestSubtitleMapArgsUnknownLanguageSkipped(t *testing.T) {
+	subFiles := []string{"en.srt", "xx.srt"}
+	languages := []string{"English", "Klingon"}
+
+	maps := subtitleMapArgs(subFiles, languages, 1)
+	got := strings.Join(maps, " ")
+
+	if !strings.Contains(got, "-map 1:s") || !strings.Contains(got, "-map 2:s") {
+		t.Errorf("subtitleMapArgs should still map every subtitle file, got %q", got)
+	}
+	if strings.Contains(got, "s:s:1") {
+		t.Errorf("subtitleMapArgs should skip the metadata tag for an unrecognized language, got %q", got)
+	}
+	if !strings.Contains(got, "s:s:0 language=eng") {
+		t.Errorf("subtitleMapArgs should still tag the recognized language, got %q", got)
+	}
+}
+
+func TestSubtitleArgsEmpty(t *testing.T) {
+	if got := subtitleInputArgs(nil); got != nil {
+		t.Errorf("subtitleInputArgs(nil) = %v, want nil", got)
+	}
+	if got := subtitleMapArgs(nil, nil, 1); got != nil {
+		t.Errorf("subtitleMapArgs(nil, nil, 1) = %v, want nil", got)
+	}
+}
@@ -1,15 +1,94 @@
-// Package extract resolves embed URLs into playable stream URLs
-// by communicating directly with MegaCloud/VidCloud endpoints.
+// Package extract resolves embed URLs into playable stream URLs. It
+// dispatches to a registry of per-host Extractor implementations rather
+// than hardcoding a single provider, so adding a new embed host is a
+// single init() registration away from cmd/provider.
 package extract
 
-import "lobster/internal/media"
+import (
+	"context"
+	"fmt"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
 
 // Extractor resolves embed URLs into playable streams.
 type Extractor interface {
-	Extract(embedURL string, preferredQuality string) (*media.Stream, error)
+	// Name returns a short identifier for the extractor (e.g. "megacloud").
+	Name() string
+
+	// Matches reports whether this extractor knows how to handle embedURL.
+	Matches(embedURL string) bool
+
+	// Extract resolves embedURL into a playable stream. ctx cancels the
+	// underlying HTTP calls (e.g. when the user aborts an fzf selection).
+	Extract(ctx context.Context, embedURL string, preferredQuality string) (*media.Stream, error)
+}
+
+// Registry dispatches embed URLs to the first registered Extractor whose
+// Matches returns true. Extractors are tried in registration order.
+type Registry struct {
+	extractors []Extractor
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds an Extractor to the registry.
+func (r *Registry) Register(e Extractor) {
+	r.extractors = append(r.extractors, e)
+}
+
+// Replace swaps the registered extractor named name for e, preserving its
+// position (and so its place in Resolve's matching order). If no extractor
+// named name is registered, e is appended instead. Used to rebuild an
+// init()-time extractor (which has no config to work with yet) once the
+// real config is loaded.
+func (r *Registry) Replace(name string, e Extractor) {
+	for i, existing := range r.extractors {
+		if existing.Name() == name {
+			r.extractors[i] = e
+			return
+		}
+	}
+	r.extractors = append(r.extractors, e)
+}
+
+// Resolve returns the first registered Extractor matching embedURL.
+func (r *Registry) Resolve(embedURL string) (Extractor, error) {
+	for _, e := range r.extractors {
+		if e.Matches(embedURL) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("no extractor registered for %q", embedURL)
+}
+
+// List returns every registered Extractor, in registration order. Used by
+// `lobster extractors` to report what's available.
+func (r *Registry) List() []Extractor {
+	return append([]Extractor(nil), r.extractors...)
+}
+
+// Default is the process-wide registry, populated by each extractor's
+// init() function.
+var Default = NewRegistry()
+
+// Extractors returns the names of every extractor registered against
+// Default, in registration order. Used by `lobster extractors` to report
+// what embed hosts are supported.
+func Extractors() []string {
+	names := make([]string, 0, len(Default.List()))
+	for _, e := range Default.List() {
+		names = append(names, e.Name())
+	}
+	return names
 }
 
-// New returns the appropriate extractor for the given embed URL.
+// New returns the default (MegaCloud) extractor, kept for callers that
+// don't need host dispatch. Prefer Default.Resolve(embedURL) instead.
 func New() Extractor {
-	return NewMegaCloud()
+	return NewMegaCloud(httputil.DefaultNetConfig())
 }
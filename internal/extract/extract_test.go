@@ -0,0 +1,81 @@
+package extract
+
+import (
+	"context"
+	"testing"
+
+	"lobster/internal/media"
+)
+
+// stubExtractor is a minimal Extractor for exercising Registry in
+// isolation from any real embed host.
+type stubExtractor struct {
+	name    string
+	matches func(string) bool
+}
+
+func (s *stubExtractor) Name() string                { return s.name }
+func (s *stubExtractor) Matches(embedURL string) bool { return s.matches(embedURL) }
+func (s *stubExtractor) Extract(ctx context.Context, embedURL, quality string) (*media.Stream, error) {
+	return &media.Stream{URL: embedURL, Quality: quality}, nil
+}
+
+func TestRegistryResolve(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubExtractor{name: "a", matches: func(u string) bool { return false }})
+	r.Register(&stubExtractor{name: "b", matches: func(u string) bool { return true }})
+
+	e, err := r.Resolve("https://example.com/embed")
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if e.Name() != "b" {
+		t.Errorf("Resolve() = %q, want %q", e.Name(), "b")
+	}
+}
+
+func TestRegistryResolveNoMatch(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubExtractor{name: "a", matches: func(u string) bool { return false }})
+
+	if _, err := r.Resolve("https://example.com/embed"); err == nil {
+		t.Fatal("Resolve() expected an error when nothing matches, got nil")
+	}
+}
+
+func TestRegistryReplacePreservesPosition(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubExtractor{name: "a", matches: func(u string) bool { return true }})
+	r.Register(&stubExtractor{name: "b", matches: func(u string) bool { return true }})
+
+	replacement := &stubExtractor{name: "a", matches: func(u string) bool { return true }}
+	r.Replace("a", replacement)
+
+	list := r.List()
+	if len(list) != 2 || list[0] != Extractor(replacement) || list[1].Name() != "b" {
+		t.Errorf("Replace() did not swap %q in place, got %+v", "a", list)
+	}
+}
+
+func TestRegistryReplaceAppendsUnknownName(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubExtractor{name: "a", matches: func(u string) bool { return true }})
+
+	r.Replace("c", &stubExtractor{name: "c", matches: func(u string) bool { return true }})
+
+	list := r.List()
+	if len(list) != 2 || list[1].Name() != "c" {
+		t.Errorf("Replace() with unknown name = %+v, want appended", list)
+	}
+}
+
+func TestRegistryList(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubExtractor{name: "a", matches: func(u string) bool { return false }})
+	r.Register(&stubExtractor{name: "b", matches: func(u string) bool { return false }})
+
+	list := r.List()
+	if len(list) != 2 || list[0].Name() != "a" || list[1].Name() != "b" {
+		t.Errorf("List() = %+v, want [a b]", list)
+	}
+}
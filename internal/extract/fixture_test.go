@@ -0,0 +1,42 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// fixtureResponse is a canned HTTP response for one URL.
+type fixtureResponse struct {
+	status int
+	body   string
+}
+
+// fixtureTransport is an http.RoundTripper that serves canned responses by
+// exact request URL, so an extractor's Extract method can be exercised
+// against a scripted embed page/API response without a real host or
+// network access.
+type fixtureTransport struct {
+	responses map[string]fixtureResponse
+}
+
+// newFixtureClient returns an *http.Client whose every request is served
+// from responses, keyed by the exact URL requested.
+func newFixtureClient(responses map[string]fixtureResponse) *http.Client {
+	return &http.Client{Transport: &fixtureTransport{responses: responses}}
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fr, ok := t.responses[req.URL.String()]
+	if !ok {
+		return nil, fmt.Errorf("fixture: no canned response registered for %s", req.URL.String())
+	}
+	return &http.Response{
+		StatusCode: fr.status,
+		Status:     http.StatusText(fr.status),
+		Body:       io.NopCloser(strings.NewReader(fr.body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,257 @@
+package extract
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"lobster/internal/media"
+)
+
+// parseMasterPlaylist parses an HLS master playlist into its variant
+// streams, alternate audio renditions, and subtitle renditions. baseURL is
+// the URL the playlist was fetched from, used to resolve relative URIs.
+// Playlists that are already media (single-rendition) playlists — i.e. have
+// no #EXT-X-STREAM-INF tags — yield a single synthetic variant pointing at
+// baseURL itself.
+func parseMasterPlaylist(data []byte, baseURL string) ([]media.Variant, []media.AudioTrack, []media.Subtitle, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parsing base URL: %w", err)
+	}
+
+	var (
+		variants  []media.Variant
+		audio     []media.AudioTrack
+		subtitles []media.Subtitle
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	var pending *media.Variant
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			v := parseStreamInf(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			pending = &v
+
+		case strings.HasPrefix(line, "#EXT-X-MEDIA:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-MEDIA:"))
+			switch attrs["TYPE"] {
+			case "AUDIO":
+				uri := attrs["URI"]
+				if uri == "" {
+					continue
+				}
+				audio = append(audio, media.AudioTrack{
+					Language: attrs["LANGUAGE"],
+					Label:    attrs["NAME"],
+					URL:      resolveURI(base, uri),
+					Default:  strings.EqualFold(attrs["DEFAULT"], "YES"),
+				})
+			case "SUBTITLES":
+				uri := attrs["URI"]
+				if uri == "" {
+					continue
+				}
+				subtitles = append(subtitles, media.Subtitle{
+					Language: attrs["LANGUAGE"],
+					Label:    attrs["NAME"],
+					URL:      resolveURI(base, uri),
+				})
+			}
+
+		case strings.HasPrefix(line, "#"):
+			// Other tags (#EXT-X-INDEPENDENT-SEGMENTS, #EXT-X-VERSION, ...) are ignored.
+			continue
+
+		default:
+			// A URI line. If it follows a #EXT-X-STREAM-INF, it's a variant;
+			// otherwise this is already a media playlist.
+			if pending != nil {
+				pending.URL = resolveURI(base, line)
+				variants = append(variants, *pending)
+				pending = nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("scanning playlist: %w", err)
+	}
+
+	if len(variants) == 0 {
+		// Already a media playlist (single rendition) — treat baseURL as the only variant.
+		variants = append(variants, media.Variant{URL: baseURL})
+	}
+
+	return variants, audio, subtitles, nil
+}
+
+// parseStreamInf parses the attribute list of an #EXT-X-STREAM-INF tag.
+func parseStreamInf(attrLine string) media.Variant {
+	attrs := parseAttributes(attrLine)
+
+	v := media.Variant{
+		Resolution: attrs["RESOLUTION"],
+		Codecs:     strings.Trim(attrs["CODECS"], `"`),
+	}
+	if bw, err := strconv.Atoi(attrs["BANDWIDTH"]); err == nil {
+		v.Bandwidth = bw
+	}
+	if fr, err := strconv.ParseFloat(attrs["FRAME-RATE"], 64); err == nil {
+		v.FrameRate = fr
+	}
+	return v
+}
+
+// parseAttributes parses a comma-separated KEY=VALUE attribute list, where
+// VALUE may be a quoted string (which itself may contain commas).
+func parseAttributes(s string) map[string]string {
+	attrs := make(map[string]string)
+
+	for len(s) > 0 {
+		eq := strings.IndexByte(s, '=')
+		if eq == -1 {
+			break
+		}
+		key := strings.TrimSpace(s[:eq])
+		rest := s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end == -1 {
+				value = strings.Trim(rest, `"`)
+				rest = ""
+			} else {
+				value = rest[1 : end+1]
+				rest = strings.TrimPrefix(rest[end+2:], ",")
+			}
+		} else {
+			comma := strings.IndexByte(rest, ',')
+			if comma == -1 {
+				value = rest
+				rest = ""
+			} else {
+				value = rest[:comma]
+				rest = rest[comma+1:]
+			}
+		}
+
+		attrs[key] = strings.TrimSpace(value)
+		s = rest
+	}
+
+	return attrs
+}
+
+// resolveURI resolves a (possibly relative) playlist URI against base.
+func resolveURI(base *url.URL, uri string) string {
+	ref, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// selectVariant resolves preferredQuality against the parsed variants.
+// Accepted forms: an explicit height like "1080p"/"1080", "best", "worst",
+// or a max-bitrate cap like "<=3000k". A height with no matching variant
+// falls back to the highest-bandwidth variant; a cap that no variant fits
+// under falls back to the lowest-bandwidth one.
+func selectVariant(variants []media.Variant, preferredQuality string) media.Variant {
+	if len(variants) == 0 {
+		return media.Variant{}
+	}
+
+	q := strings.ToLower(strings.TrimSpace(preferredQuality))
+
+	switch q {
+	case "best", "":
+		return bestVariant(variants)
+	case "worst":
+		return worstVariant(variants)
+	}
+
+	if capBw, ok := parseMaxBitrate(q); ok {
+		best := variants[0]
+		found := false
+		for _, v := range variants {
+			if v.Bandwidth <= capBw && (!found || v.Bandwidth > best.Bandwidth) {
+				best = v
+				found = true
+			}
+		}
+		if found {
+			return best
+		}
+		return worstVariant(variants)
+	}
+
+	// Match by height, e.g. "1080p" or "1080".
+	wantHeight := strings.TrimSuffix(q, "p")
+	for _, v := range variants {
+		if variantHeight(v) == wantHeight {
+			return v
+		}
+	}
+
+	return bestVariant(variants)
+}
+
+func bestVariant(variants []media.Variant) media.Variant {
+	best := variants[0]
+	for _, v := range variants {
+		if v.Bandwidth > best.Bandwidth {
+			best = v
+		}
+	}
+	return best
+}
+
+func worstVariant(variants []media.Variant) media.Variant {
+	worst := variants[0]
+	for _, v := range variants {
+		if v.Bandwidth < worst.Bandwidth {
+			worst = v
+		}
+	}
+	return worst
+}
+
+// parseMaxBitrate parses a "<=3000k" style cap into bits/sec.
+func parseMaxBitrate(q string) (int, bool) {
+	if !strings.HasPrefix(q, "<=") {
+		return 0, false
+	}
+	rest := strings.TrimPrefix(q, "<=")
+	mult := 1
+	if strings.HasSuffix(rest, "k") {
+		mult = 1000
+		rest = strings.TrimSuffix(rest, "k")
+	} else if strings.HasSuffix(rest, "m") {
+		mult = 1_000_000
+		rest = strings.TrimSuffix(rest, "m")
+	}
+	n, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, false
+	}
+	return n * mult, true
+}
+
+// variantHeight extracts the height from a RESOLUTION attribute like "1920x1080".
+func variantHeight(v media.Variant) string {
+	parts := strings.Split(v.Resolution, "x")
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[1]
+}
@@ -0,0 +1,78 @@
+package extract
+
+import "testing"
+
+const samplePlaylist = `#EXTM3U
+#EXT-X-INDEPENDENT-SEGMENTS
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="English",LANGUAGE="en",DEFAULT=YES,URI="audio/en/index.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="English",LANGUAGE="en",URI="subs/en.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360,CODECS="avc1.64001f,mp4a.40.2",FRAME-RATE=24.000
+360/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=2800000,RESOLUTION=1280x720,CODECS="avc1.64001f,mp4a.40.2"
+720/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=5000000,RESOLUTION=1920x1080,CODECS="avc1.640028,mp4a.40.2"
+1080/index.m3u8
+`
+
+func TestParseMasterPlaylist(t *testing.T) {
+	variants, audio, subs, err := parseMasterPlaylist([]byte(samplePlaylist), "https://example.com/master.m3u8")
+	if err != nil {
+		t.Fatalf("parseMasterPlaylist() error: %v", err)
+	}
+
+	if len(variants) != 3 {
+		t.Fatalf("got %d variants, want 3", len(variants))
+	}
+	if variants[2].Resolution != "1920x1080" || variants[2].Bandwidth != 5000000 {
+		t.Errorf("unexpected variant: %+v", variants[2])
+	}
+	if variants[0].URL != "https://example.com/360/index.m3u8" {
+		t.Errorf("relative URI not resolved: %q", variants[0].URL)
+	}
+
+	if len(audio) != 1 || audio[0].URL != "https://example.com/audio/en/index.m3u8" {
+		t.Errorf("unexpected audio tracks: %+v", audio)
+	}
+	if len(subs) != 1 || subs[0].URL != "https://example.com/subs/en.m3u8" {
+		t.Errorf("unexpected subtitle renditions: %+v", subs)
+	}
+}
+
+func TestParseMasterPlaylistSingleRendition(t *testing.T) {
+	// A playlist with no #EXT-X-STREAM-INF is already a media playlist.
+	data := "#EXTM3U\n#EXT-X-VERSION:3\n#EXTINF:10.0,\nseg0.ts\n"
+	variants, _, _, err := parseMasterPlaylist([]byte(data), "https://example.com/media.m3u8")
+	if err != nil {
+		t.Fatalf("parseMasterPlaylist() error: %v", err)
+	}
+	if len(variants) != 1 || variants[0].URL != "https://example.com/media.m3u8" {
+		t.Errorf("expected single synthetic variant, got %+v", variants)
+	}
+}
+
+func TestSelectVariant(t *testing.T) {
+	variants, _, _, err := parseMasterPlaylist([]byte(samplePlaylist), "https://example.com/master.m3u8")
+	if err != nil {
+		t.Fatalf("parseMasterPlaylist() error: %v", err)
+	}
+
+	tests := []struct {
+		quality  string
+		wantRes  string
+	}{
+		{"best", "1920x1080"},
+		{"worst", "640x360"},
+		{"720p", "1280x720"},
+		{"720", "1280x720"},
+		{"<=3000k", "1280x720"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.quality, func(t *testing.T) {
+			got := selectVariant(variants, tt.quality)
+			if got.Resolution != tt.wantRes {
+				t.Errorf("selectVariant(%q) resolution = %q, want %q", tt.quality, got.Resolution, tt.wantRes)
+			}
+		})
+	}
+}
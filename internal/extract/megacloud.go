@@ -1,6 +1,7 @@
 package extract
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,13 +10,23 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
+	"lobster/internal/cache"
 	"lobster/internal/httputil"
 	"lobster/internal/media"
 )
 
 const (
 	megacloudKeysURL = "https://raw.githubusercontent.com/yogesh-hacker/MegacloudKeys/refs/heads/main/keys.json"
+
+	// megacloudKeyTTL bounds how long the decryption key is trusted before
+	// it's re-validated (with If-None-Match) against the upstream gist.
+	megacloudKeyTTL = 6 * time.Hour
+
+	// megacloudSourceTTL is short: it only exists to make re-opening the
+	// episode the user just watched instant, not to serve stale sources.
+	megacloudSourceTTL = 5 * time.Minute
 )
 
 // MegaCloudExtractor extracts streams from MegaCloud/VidCloud embed URLs.
@@ -25,15 +36,42 @@ type MegaCloudExtractor struct {
 	// Cached megacloud keys
 	keysMu sync.Mutex
 	keys   map[string]string
+
+	// keyCache persists the decryption key across process runs.
+	// sourceCache persists getSources responses, keyed by sourceID+clientKey.
+	// Both are nil-safe: if the cache directory can't be created, caching is
+	// silently skipped and extraction falls back to always fetching fresh.
+	keyCache    *cache.Store
+	sourceCache *cache.Store
 }
 
-// NewMegaCloud creates a new MegaCloudExtractor.
-func NewMegaCloud() *MegaCloudExtractor {
+// NewMegaCloud creates a new MegaCloudExtractor using netCfg for request
+// timeouts, retries, and proxying. Pass httputil.DefaultNetConfig() for
+// the previous hardcoded behavior.
+func NewMegaCloud(netCfg httputil.NetConfig) *MegaCloudExtractor {
+	keyCache, _ := cache.New("megacloud-keys")
+	sourceCache, _ := cache.New("megacloud-sources")
 	return &MegaCloudExtractor{
-		client: httputil.NewClient(),
+		client:      httputil.NewClient(netCfg),
+		keyCache:    keyCache,
+		sourceCache: sourceCache,
 	}
 }
 
+func init() {
+	Default.Register(NewMegaCloud(httputil.DefaultNetConfig()))
+}
+
+// Name identifies this extractor in the registry.
+func (m *MegaCloudExtractor) Name() string { return "megacloud" }
+
+// Matches reports whether embedURL points at a MegaCloud/VidCloud embed.
+// These hosts rotate frequently, so we match on the embed-N/v3/e-1 path
+// shape rather than a fixed domain allowlist.
+func (m *MegaCloudExtractor) Matches(embedURL string) bool {
+	return strings.Contains(embedURL, "/v3/e-1/")
+}
+
 // sourcesResponse represents the JSON from the getSources endpoint.
 type sourcesResponse struct {
 	Sources   json.RawMessage `json:"sources"`
@@ -53,8 +91,10 @@ type source struct {
 	Type string `json:"type"`
 }
 
-// Extract resolves an embed URL into a playable stream.
-func (m *MegaCloudExtractor) Extract(embedURL string, preferredQuality string) (*media.Stream, error) {
+// Extract resolves an embed URL into a playable stream. ctx cancels all
+// underlying HTTP calls, so an aborted fzf selection or Ctrl-C during
+// playback setup doesn't leave requests running in the background.
+func (m *MegaCloudExtractor) Extract(ctx context.Context, embedURL string, preferredQuality string) (*media.Stream, error) {
 	if err := httputil.ValidateURL(embedURL); err != nil {
 		return nil, fmt.Errorf("invalid embed URL: %w", err)
 	}
@@ -67,7 +107,7 @@ func (m *MegaCloudExtractor) Extract(embedURL string, preferredQuality string) (
 
 	// Step 1: Fetch embed page HTML to get the client key
 	embedPageURL := fmt.Sprintf("https://%s/%s/v3/e-1/%s?z=", domain, embedPrefix, sourceID)
-	embedHTML, err := m.fetchHTML(embedPageURL, "https://flixhq.to/")
+	embedHTML, err := m.fetchHTML(ctx, embedPageURL, "https://flixhq.to/")
 	if err != nil {
 		return nil, fmt.Errorf("fetching embed page: %w", err)
 	}
@@ -78,13 +118,29 @@ func (m *MegaCloudExtractor) Extract(embedURL string, preferredQuality string) (
 		return nil, fmt.Errorf("extracting client key: %w", err)
 	}
 
-	// Step 3: Call getSources endpoint
-	getSourcesURL := fmt.Sprintf("https://%s/%s/v3/e-1/getSources?id=%s&_k=%s",
-		domain, embedPrefix, url.QueryEscape(sourceID), url.QueryEscape(clientKey))
+	// Step 3: Call getSources endpoint, reusing a short-lived cached
+	// response for the same source+key pair so restarting playback of an
+	// episode the user just watched doesn't re-hit the host.
+	sourceCacheKey := sourceID + "|" + clientKey
+	var body []byte
+	if m.sourceCache != nil {
+		if entry, ok := m.sourceCache.Get(sourceCacheKey); ok && entry.Fresh {
+			body = entry.Data
+		}
+	}
+	if body == nil {
+		getSourcesURL := fmt.Sprintf("https://%s/%s/v3/e-1/getSources?id=%s&_k=%s",
+			domain, embedPrefix, url.QueryEscape(sourceID), url.QueryEscape(clientKey))
 
-	body, err := m.fetchJSON(getSourcesURL, embedURL)
-	if err != nil {
-		return nil, fmt.Errorf("fetching sources: %w", err)
+		fetched, err := m.fetchJSON(ctx, getSourcesURL, embedURL)
+		if err != nil {
+			return nil, fmt.Errorf("fetching sources: %w", err)
+		}
+		body = fetched
+
+		if m.sourceCache != nil {
+			_ = m.sourceCache.Set(sourceCacheKey, body, megacloudSourceTTL, "")
+		}
 	}
 
 	// Step 4: Parse response
@@ -103,7 +159,7 @@ func (m *MegaCloudExtractor) Extract(embedURL string, preferredQuality string) (
 		}
 
 		// Fetch megacloud key
-		megaKey, err := m.getMegacloudKey()
+		megaKey, err := m.getMegacloudKey(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("fetching megacloud key: %w", err)
 		}
@@ -136,6 +192,26 @@ func (m *MegaCloudExtractor) Extract(embedURL string, preferredQuality string) (
 		}
 	}
 
+	// Step 6b: MegaCloud almost always returns a single master .m3u8 rather
+	// than one URL per quality, so parse it and resolve preferredQuality
+	// against the actual variant list.
+	var variants []media.Variant
+	var audioTracks []media.AudioTrack
+	if strings.Contains(streamURL, ".m3u8") {
+		playlist, err := m.fetchHTML(ctx, streamURL, embedURL)
+		if err == nil {
+			vs, audio, subs, err := parseMasterPlaylist([]byte(playlist), streamURL)
+			if err == nil {
+				variants = vs
+				audioTracks = audio
+				resp.Tracks = append(resp.Tracks, subtitleTracksFromHLS(subs)...)
+				if best := selectVariant(vs, preferredQuality); best.URL != "" {
+					streamURL = best.URL
+				}
+			}
+		}
+	}
+
 	// Step 7: Map subtitle tracks
 	var subtitles []media.Subtitle
 	for _, t := range resp.Tracks {
@@ -150,12 +226,24 @@ func (m *MegaCloudExtractor) Extract(embedURL string, preferredQuality string) (
 	}
 
 	return &media.Stream{
-		URL:       streamURL,
-		Subtitles: subtitles,
-		Quality:   preferredQuality,
+		URL:         streamURL,
+		Subtitles:   subtitles,
+		Quality:     preferredQuality,
+		Variants:    variants,
+		AudioTracks: audioTracks,
 	}, nil
 }
 
+// subtitleTracksFromHLS adapts #EXT-X-MEDIA:TYPE=SUBTITLES renditions found
+// in the master playlist into the same track shape as getSources' "tracks".
+func subtitleTracksFromHLS(subs []media.Subtitle) []track {
+	tracks := make([]track, len(subs))
+	for i, s := range subs {
+		tracks[i] = track{File: s.URL, Label: s.Label, Kind: "captions"}
+	}
+	return tracks
+}
+
 // parseEmbedURL extracts domain, embed prefix, and source ID from an embed URL.
 // Example: https://streameeeeee.site/embed-1/v3/e-1/AbCdEf?z= -> ("streameeeeee.site", "embed-1", "AbCdEf")
 func parseEmbedURL(embedURL string) (domain, embedPrefix, sourceID string, err error) {
@@ -195,7 +283,7 @@ func parseEmbedURL(embedURL string) (domain, embedPrefix, sourceID string, err e
 }
 
 // fetchHTML fetches a page and returns its HTML body.
-func (m *MegaCloudExtractor) fetchHTML(pageURL, referer string) (string, error) {
+func (m *MegaCloudExtractor) fetchHTML(ctx context.Context, pageURL, referer string) (string, error) {
 	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("creating request: %w", err)
@@ -208,9 +296,9 @@ func (m *MegaCloudExtractor) fetchHTML(pageURL, referer string) (string, error)
 		req.Header.Set("Referer", referer)
 	}
 
-	resp, err := m.client.Do(req)
+	resp, err := httputil.Do(ctx, m.client, req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -227,7 +315,7 @@ func (m *MegaCloudExtractor) fetchHTML(pageURL, referer string) (string, error)
 }
 
 // fetchJSON fetches a JSON endpoint and returns the raw body.
-func (m *MegaCloudExtractor) fetchJSON(apiURL, referer string) ([]byte, error) {
+func (m *MegaCloudExtractor) fetchJSON(ctx context.Context, apiURL, referer string) ([]byte, error) {
 	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -241,9 +329,9 @@ func (m *MegaCloudExtractor) fetchJSON(apiURL, referer string) ([]byte, error) {
 		req.Header.Set("Referer", referer)
 	}
 
-	resp, err := m.client.Do(req)
+	resp, err := httputil.Do(ctx, m.client, req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -259,8 +347,16 @@ func (m *MegaCloudExtractor) fetchJSON(apiURL, referer string) ([]byte, error) {
 	return body, nil
 }
 
-// getMegacloudKey fetches and caches the megacloud decryption key.
-func (m *MegaCloudExtractor) getMegacloudKey() (string, error) {
+// megacloudKeyCacheKey is the only entry the keyCache store holds; it's
+// namespaced by directory (megacloud-keys), not by content, so a fixed
+// name is fine.
+const megacloudKeyCacheKey = "keys.json"
+
+// getMegacloudKey fetches and caches the megacloud decryption key. The key
+// is kept in memory for the life of the extractor and on disk for
+// megacloudKeyTTL, re-validated with If-None-Match on refresh so a 304
+// doesn't cost more than the in-memory cache already saves.
+func (m *MegaCloudExtractor) getMegacloudKey(ctx context.Context) (string, error) {
 	m.keysMu.Lock()
 	defer m.keysMu.Unlock()
 
@@ -270,11 +366,37 @@ func (m *MegaCloudExtractor) getMegacloudKey() (string, error) {
 		}
 	}
 
-	body, err := httputil.GetJSON(m.client, megacloudKeysURL)
+	var cached cache.Entry
+	var haveCached bool
+	if m.keyCache != nil {
+		cached, haveCached = m.keyCache.Get(megacloudKeyCacheKey)
+		if haveCached && cached.Fresh {
+			var keys map[string]string
+			if err := json.Unmarshal(cached.Data, &keys); err == nil {
+				if key, ok := keys["mega"]; ok {
+					m.keys = keys
+					return key, nil
+				}
+			}
+		}
+	}
+
+	etag := ""
+	if haveCached {
+		etag = cached.ETag
+	}
+
+	body, respETag, notModified, err := m.fetchKeysJSON(ctx, etag)
 	if err != nil {
 		return "", fmt.Errorf("fetching megacloud keys: %w", err)
 	}
 
+	if notModified && haveCached {
+		body = cached.Data
+	} else if m.keyCache != nil {
+		_ = m.keyCache.Set(megacloudKeyCacheKey, body, megacloudKeyTTL, respETag)
+	}
+
 	var keys map[string]string
 	if err := json.Unmarshal(body, &keys); err != nil {
 		return "", fmt.Errorf("parsing megacloud keys: %w", err)
@@ -289,3 +411,39 @@ func (m *MegaCloudExtractor) getMegacloudKey() (string, error) {
 
 	return key, nil
 }
+
+// fetchKeysJSON fetches the megacloud keys endpoint, sending If-None-Match
+// when etag is non-empty. A 304 response is reported via notModified, in
+// which case body is empty and the caller should fall back to its cached
+// copy.
+func (m *MegaCloudExtractor) fetchKeysJSON(ctx context.Context, etag string) (body []byte, respETag string, notModified bool, err error) {
+	req, err := http.NewRequest(http.MethodGet, megacloudKeysURL, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/121.0")
+	req.Header.Set("Accept", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := httputil.Do(ctx, m.client, req)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	body, err = io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, "", false, fmt.Errorf("reading response: %w", err)
+	}
+
+	return body, resp.Header.Get("ETag"), false, nil
+}
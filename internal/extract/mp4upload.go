@@ -0,0 +1,73 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+// mp4uploadSrcPattern matches the jwplayer source URL embedded in the
+// Mp4upload player page, e.g. player.setup({ ..., src: "https://...mp4", ... }).
+var mp4uploadSrcPattern = regexp.MustCompile(`src:\s*"([^"]+\.mp4[^"]*)"`)
+
+// Mp4UploadExtractor extracts direct MP4 links from mp4upload.com embeds.
+type Mp4UploadExtractor struct {
+	client *http.Client
+}
+
+// NewMp4Upload creates a new Mp4UploadExtractor.
+func NewMp4Upload() *Mp4UploadExtractor {
+	return &Mp4UploadExtractor{client: httputil.NewClient(httputil.DefaultNetConfig())}
+}
+
+func init() {
+	Default.Register(NewMp4Upload())
+}
+
+// Name identifies this extractor in the registry.
+func (e *Mp4UploadExtractor) Name() string { return "mp4upload" }
+
+// Matches reports whether embedURL is an mp4upload.com embed.
+func (e *Mp4UploadExtractor) Matches(embedURL string) bool {
+	return strings.Contains(embedURL, "mp4upload.com")
+}
+
+// Extract resolves an mp4upload embed URL into a playable stream. Mp4upload
+// serves a single direct MP4 link (no quality choice), so preferredQuality
+// is accepted but unused.
+func (e *Mp4UploadExtractor) Extract(ctx context.Context, embedURL string, preferredQuality string) (*media.Stream, error) {
+	if err := httputil.ValidateURL(embedURL); err != nil {
+		return nil, fmt.Errorf("invalid embed URL: %w", err)
+	}
+
+	resp, err := httputil.GetCtx(ctx, e.client, embedURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching embed page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, embedURL)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("reading embed page: %w", err)
+	}
+
+	match := mp4uploadSrcPattern.FindStringSubmatch(string(body))
+	if match == nil {
+		return nil, fmt.Errorf("could not find mp4 source in mp4upload embed")
+	}
+
+	return &media.Stream{
+		URL:     match[1],
+		Quality: preferredQuality,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package extract
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMp4UploadExtractorExtract(t *testing.T) {
+	const embedURL = "https://mp4upload.com/embed-abc123"
+	const page = `<script>
+		var player = jwplayer("player").setup({
+			src: "https://cdn.mp4upload.com/d/abc/video.mp4",
+			image: "https://cdn.mp4upload.com/d/abc/thumb.jpg"
+		});
+	</script>`
+
+	e := &Mp4UploadExtractor{client: newFixtureClient(map[string]fixtureResponse{
+		embedURL: {status: 200, body: page},
+	})}
+
+	stream, err := e.Extract(context.Background(), embedURL, "720")
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+	if stream.URL != "https://cdn.mp4upload.com/d/abc/video.mp4" {
+		t.Errorf("stream.URL = %q, want the jwplayer src", stream.URL)
+	}
+	if stream.Quality != "720" {
+		t.Errorf("stream.Quality = %q, want %q", stream.Quality, "720")
+	}
+}
+
+func TestMp4UploadExtractorExtractNoSource(t *testing.T) {
+	const embedURL = "https://mp4upload.com/embed-missing"
+
+	e := &Mp4UploadExtractor{client: newFixtureClient(map[string]fixtureResponse{
+		embedURL: {status: 200, body: "<html>no player here</html>"},
+	})}
+
+	if _, err := e.Extract(context.Background(), embedURL, "720"); err == nil {
+		t.Fatal("Extract() expected an error when no mp4 source is present, got nil")
+	}
+}
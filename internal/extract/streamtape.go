@@ -0,0 +1,115 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+// streamtapeHosts lists the known domains for StreamTape embeds.
+var streamtapeHosts = []string{"streamtape.com", "streamtape.to", "streamtape.net"}
+
+// StreamTape splits its direct video link across two places in the embed
+// page: a base URL assigned to the (hidden) "robotlink" element, missing
+// its first few characters, and a literal suffix string the page's own
+// script substrings and appends back on. Like MegaCloud's client-key
+// hiding (see extractClientKey), this obfuscation shifts over time — if
+// extraction starts failing, these are the patterns to update first.
+var (
+	streamtapeLinkPattern   = regexp.MustCompile(`id=['"]robotlink['"][^>]*>([^<]+)<`)
+	streamtapeSuffixPattern = regexp.MustCompile(`innerHTML\s*=.*?\+\s*\('([^']*)'\)\.substring\((\d+)\)`)
+)
+
+// StreamTapeExtractor extracts direct video links from streamtape.com
+// embeds.
+type StreamTapeExtractor struct {
+	client *http.Client
+}
+
+// NewStreamTape creates a new StreamTapeExtractor.
+func NewStreamTape() *StreamTapeExtractor {
+	return &StreamTapeExtractor{client: httputil.NewClient(httputil.DefaultNetConfig())}
+}
+
+func init() {
+	Default.Register(NewStreamTape())
+}
+
+// Name identifies this extractor in the registry.
+func (e *StreamTapeExtractor) Name() string { return "streamtape" }
+
+// Matches reports whether embedURL is a streamtape.com embed.
+func (e *StreamTapeExtractor) Matches(embedURL string) bool {
+	for _, host := range streamtapeHosts {
+		if strings.Contains(embedURL, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract resolves a StreamTape embed URL into a playable stream.
+// StreamTape serves a single direct link (no quality choice), so
+// preferredQuality is accepted but unused.
+func (e *StreamTapeExtractor) Extract(ctx context.Context, embedURL string, preferredQuality string) (*media.Stream, error) {
+	if err := httputil.ValidateURL(embedURL); err != nil {
+		return nil, fmt.Errorf("invalid embed URL: %w", err)
+	}
+
+	resp, err := httputil.GetCtx(ctx, e.client, embedURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching embed page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, embedURL)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("reading embed page: %w", err)
+	}
+
+	link, err := parseStreamTapeLink(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return &media.Stream{
+		URL:     link,
+		Quality: preferredQuality,
+	}, nil
+}
+
+// parseStreamTapeLink reconstructs the direct video link from the embed
+// page's split robotlink base + substringed suffix.
+func parseStreamTapeLink(html string) (string, error) {
+	baseMatch := streamtapeLinkPattern.FindStringSubmatch(html)
+	if baseMatch == nil {
+		return "", fmt.Errorf("could not find robotlink base in streamtape embed")
+	}
+
+	suffixMatch := streamtapeSuffixPattern.FindStringSubmatch(html)
+	if suffixMatch == nil {
+		return "", fmt.Errorf("could not find link suffix in streamtape embed")
+	}
+
+	offset, err := strconv.Atoi(suffixMatch[2])
+	if err != nil || offset > len(suffixMatch[1]) {
+		return "", fmt.Errorf("invalid streamtape suffix offset %q", suffixMatch[2])
+	}
+
+	link := baseMatch[1] + suffixMatch[1][offset:]
+	if strings.HasPrefix(link, "//") {
+		link = "https:" + link
+	}
+	return link, nil
+}
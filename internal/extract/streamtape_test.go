@@ -0,0 +1,28 @@
+package extract
+
+import "testing"
+
+func TestParseStreamTapeLink(t *testing.T) {
+	const html = `
+	<div id="robotlink" style="display:none;">//streamtape.com/get_video?id=abc123&expires=999&ip=1.2.3.4&token=part</div>
+	<script>
+	document.getElementById('ideoolink').innerHTML = document.getElementById('robotlink').innerHTML + ('xxxxxsuffixtoken').substring(5)
+	</script>
+	`
+
+	link, err := parseStreamTapeLink(html)
+	if err != nil {
+		t.Fatalf("parseStreamTapeLink() error: %v", err)
+	}
+
+	const want = "https://streamtape.com/get_video?id=abc123&expires=999&ip=1.2.3.4&token=partsuffixtoken"
+	if link != want {
+		t.Errorf("parseStreamTapeLink() = %q, want %q", link, want)
+	}
+}
+
+func TestParseStreamTapeLinkMissingRobotlink(t *testing.T) {
+	if _, err := parseStreamTapeLink("<html>nothing here</html>"); err == nil {
+		t.Fatal("expected an error when the embed page has no robotlink element")
+	}
+}
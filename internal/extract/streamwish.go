@@ -0,0 +1,86 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+// streamwishHosts lists the known domains for the StreamWish/DoodStream/
+// Filemoon family of embeds, which all serve a packed-JS player exposing a
+// single HLS source.
+var streamwishHosts = []string{
+	"streamwish.com", "streamwish.to",
+	"doodstream.com", "dood.to", "dood.watch",
+	"filemoon.sx", "filemoon.to",
+}
+
+// streamwishSrcPattern matches the HLS source assigned inside the player's
+// (often eval-packed) JS, e.g. sources: [{file:"https://....m3u8"}].
+var streamwishSrcPattern = regexp.MustCompile(`file\s*:\s*"([^"]+\.m3u8[^"]*)"`)
+
+// StreamWishExtractor extracts HLS links from StreamWish-family embeds.
+type StreamWishExtractor struct {
+	client *http.Client
+}
+
+// NewStreamWish creates a new StreamWishExtractor.
+func NewStreamWish() *StreamWishExtractor {
+	return &StreamWishExtractor{client: httputil.NewClient(httputil.DefaultNetConfig())}
+}
+
+func init() {
+	Default.Register(NewStreamWish())
+}
+
+// Name identifies this extractor in the registry.
+func (e *StreamWishExtractor) Name() string { return "streamwish" }
+
+// Matches reports whether embedURL belongs to the StreamWish/DoodStream/
+// Filemoon family.
+func (e *StreamWishExtractor) Matches(embedURL string) bool {
+	for _, host := range streamwishHosts {
+		if strings.Contains(embedURL, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract resolves a StreamWish-family embed URL into a playable stream.
+func (e *StreamWishExtractor) Extract(ctx context.Context, embedURL string, preferredQuality string) (*media.Stream, error) {
+	if err := httputil.ValidateURL(embedURL); err != nil {
+		return nil, fmt.Errorf("invalid embed URL: %w", err)
+	}
+
+	resp, err := httputil.GetCtx(ctx, e.client, embedURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching embed page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, embedURL)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("reading embed page: %w", err)
+	}
+
+	match := streamwishSrcPattern.FindStringSubmatch(string(body))
+	if match == nil {
+		return nil, fmt.Errorf("could not find HLS source in embed page")
+	}
+
+	return &media.Stream{
+		URL:     match[1],
+		Quality: preferredQuality,
+	}, nil
+}
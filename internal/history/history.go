@@ -18,6 +18,26 @@ import (
 // TSV columns: id, title, type, season, episode, position, duration
 const numColumns = 7
 
+// RemoteSync mirrors local history to an external account (e.g. Trakt.tv).
+// Pull's progress is merged into the entries Load returns (the local TSV
+// itself is not rewritten by Load); Push mirrors a
+// saved entry to the remote on Save. Implementations should treat both as
+// best-effort: a sync failure must never block local history operations.
+type RemoteSync interface {
+	Pull() ([]media.HistoryEntry, error)
+	Push(entry media.HistoryEntry) error
+}
+
+// sync is the optional remote backend, wired up via SetRemoteSync.
+// Unset by default so history works entirely offline.
+var sync RemoteSync
+
+// SetRemoteSync installs (or clears, with nil) the remote sync backend
+// used by Load and Save.
+func SetRemoteSync(s RemoteSync) {
+	sync = s
+}
+
 // Load reads the history file and returns all entries.
 func Load() ([]media.HistoryEntry, error) {
 	path, err := config.HistoryPath()
@@ -54,9 +74,45 @@ func Load() ([]media.HistoryEntry, error) {
 		return nil, fmt.Errorf("reading history: %w", err)
 	}
 
+	if sync != nil {
+		entries = mergeRemote(entries, sync)
+	}
+
 	return entries, nil
 }
 
+// mergeRemote folds remote entries into the local set, preferring whichever
+// side has made more progress for a given id/season/episode. Remote errors
+// are swallowed — a dead network shouldn't break local history.
+func mergeRemote(local []media.HistoryEntry, s RemoteSync) []media.HistoryEntry {
+	remote, err := s.Pull()
+	if err != nil {
+		return local
+	}
+
+	byKey := make(map[string]int, len(local))
+	for i, e := range local {
+		byKey[historyKey(e)] = i
+	}
+
+	for _, re := range remote {
+		if i, ok := byKey[historyKey(re)]; ok {
+			if re.Position > local[i].Position {
+				local[i] = re
+			}
+			continue
+		}
+		byKey[historyKey(re)] = len(local)
+		local = append(local, re)
+	}
+
+	return local
+}
+
+func historyKey(e media.HistoryEntry) string {
+	return fmt.Sprintf("%s\t%d\t%d", e.ID, e.Season, e.Episode)
+}
+
 // Save writes or updates an entry in the history file.
 // Uses atomic write (write to temp file, then rename) to prevent corruption.
 func Save(entry media.HistoryEntry) error {
@@ -122,6 +178,10 @@ func Save(entry media.HistoryEntry) error {
 		return fmt.Errorf("renaming history file: %w", err)
 	}
 
+	if sync != nil {
+		_ = sync.Push(entry) // Best-effort; local write already succeeded.
+	}
+
 	return nil
 }
 
@@ -1,78 +1,314 @@
-// Package httputil provides a security-hardened HTTP client and input sanitization utilities.
-package httputil
-
-import (
-	"crypto/tls"
-	"fmt"
-	"io"
-	"net/http"
-	"time"
-)
-
-// NewClient creates a hardened HTTP client with secure defaults.
-func NewClient() *http.Client {
-	return &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion: tls.VersionTLS12,
-			},
-			ForceAttemptHTTP2:   true,
-			MaxIdleConns:        10,
-			IdleConnTimeout:     30 * time.Second,
-			DisableCompression:  false,
-			MaxIdleConnsPerHost: 5,
-		},
-	}
-}
-
-// Get performs a GET request with standard browser-like headers.
-func Get(client *http.Client, url string) (*http.Response, error) {
-	if err := ValidateURL(url); err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
-	}
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/121.0")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-
-	return client.Do(req)
-}
-
-// GetJSON performs a GET request with JSON accept header.
-func GetJSON(client *http.Client, url string) ([]byte, error) {
-	if err := ValidateURL(url); err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
-	}
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/121.0")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, url)
-	}
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024)) // 10MB limit
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
-	}
-
-	return body, nil
-}
+// Package httputil provides a security-hardened HTTP client and input sanitization utilities.
+package httputil
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NetConfig configures the HTTP clients extractors and decryptors build
+// with NewClient. It's populated from the [network] section of the config
+// file; zero values fall back to DefaultNetConfig's values where it makes
+// sense (see withDefaults).
+type NetConfig struct {
+	// RequestTimeoutSec bounds a single HTTP round trip (one attempt,
+	// before retries). 0 uses DefaultNetConfig's value.
+	RequestTimeoutSec int `toml:"request_timeout_sec"`
+
+	// TotalTimeoutSec sets http.Client.Timeout, which bounds each attempt
+	// Do makes (including reading its body), not the retry loop as a
+	// whole. -1 disables it (matches Zeno's convention), 0 uses
+	// DefaultNetConfig's value.
+	TotalTimeoutSec int `toml:"total_timeout_sec"`
+
+	// MaxRetries bounds the retry-with-backoff loop in Do for 429/5xx/
+	// timeout responses. 0 uses DefaultNetConfig's value.
+	MaxRetries int `toml:"max_retries"`
+
+	// RetryBackoffMs is the base backoff between retries when the server
+	// doesn't send a Retry-After header. 0 uses DefaultNetConfig's value.
+	RetryBackoffMs int `toml:"retry_backoff_ms"`
+
+	// MaxConcurrentRequests caps how many requests Do lets run at once,
+	// process-wide. 0 means unlimited.
+	MaxConcurrentRequests int `toml:"max_concurrent_requests"`
+
+	// UserAgent overrides the default browser-like User-Agent sent by
+	// GetCtx/GetJSONCtx. Empty uses DefaultNetConfig's value.
+	UserAgent string `toml:"user_agent"`
+
+	// ProxyURL routes all requests through this proxy (e.g.
+	// "http://127.0.0.1:8080"). Empty means no proxy.
+	ProxyURL string `toml:"proxy_url"`
+}
+
+// defaultUserAgent is sent by GetCtx/GetJSONCtx unless NetConfig.UserAgent
+// overrides it.
+const defaultUserAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/121.0"
+
+// DefaultNetConfig returns the settings lobster used before [network] was
+// configurable.
+func DefaultNetConfig() NetConfig {
+	return NetConfig{
+		RequestTimeoutSec: 30,
+		TotalTimeoutSec:   -1,
+		MaxRetries:        3,
+		RetryBackoffMs:    500,
+		UserAgent:         defaultUserAgent,
+	}
+}
+
+// withDefaults fills in zero fields of cfg with DefaultNetConfig's values,
+// so callers can pass a partially-populated NetConfig (e.g. straight from
+// a config file that only sets proxy_url).
+func (cfg NetConfig) withDefaults() NetConfig {
+	d := DefaultNetConfig()
+	if cfg.RequestTimeoutSec == 0 {
+		cfg.RequestTimeoutSec = d.RequestTimeoutSec
+	}
+	if cfg.TotalTimeoutSec == 0 {
+		cfg.TotalTimeoutSec = d.TotalTimeoutSec
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = d.MaxRetries
+	}
+	if cfg.RetryBackoffMs == 0 {
+		cfg.RetryBackoffMs = d.RetryBackoffMs
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = d.UserAgent
+	}
+	return cfg
+}
+
+// NewClient creates a hardened HTTP client configured per cfg. Pass
+// DefaultNetConfig() for the previous hardcoded behavior.
+func NewClient(cfg NetConfig) *http.Client {
+	cfg = cfg.withDefaults()
+
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+		DisableCompression:  false,
+		MaxIdleConnsPerHost: 5,
+	}
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	client := &http.Client{Transport: transport}
+	if cfg.TotalTimeoutSec >= 0 {
+		client.Timeout = time.Duration(cfg.TotalTimeoutSec) * time.Second
+	}
+
+	requestState.set(client, cfg)
+	return client
+}
+
+// clientState tracks the per-NetConfig knobs Do needs but *http.Client
+// doesn't have room for (request timeout, retry count/backoff, user
+// agent). Keyed by client pointer since Do/GetCtx only take *http.Client.
+type clientState struct {
+	mu    sync.Mutex
+	byPtr map[*http.Client]NetConfig
+}
+
+var requestState = &clientState{byPtr: make(map[*http.Client]NetConfig)}
+
+func (s *clientState) set(client *http.Client, cfg NetConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byPtr[client] = cfg
+}
+
+func (s *clientState) get(client *http.Client) NetConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cfg, ok := s.byPtr[client]; ok {
+		return cfg
+	}
+	return DefaultNetConfig()
+}
+
+// concurrencySem bounds how many requests Do lets run at once,
+// process-wide, when a client's NetConfig.MaxConcurrentRequests is set.
+// Reset via setMaxConcurrent whenever a client configures a limit.
+var (
+	concurrencyMu  sync.Mutex
+	concurrencySem chan struct{}
+)
+
+func acquireConcurrencySlot(ctx context.Context, max int) (func(), error) {
+	if max <= 0 {
+		return func() {}, nil
+	}
+
+	concurrencyMu.Lock()
+	if concurrencySem == nil || cap(concurrencySem) != max {
+		concurrencySem = make(chan struct{}, max)
+	}
+	sem := concurrencySem
+	concurrencyMu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Do sends req, honoring ctx cancellation and retrying 429/5xx responses
+// with backoff (using Retry-After when the server provides one). It also
+// blocks on the per-host rate limiter before each attempt, so bursts of
+// requests to the same embed/provider host don't get us blocked.
+func Do(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	cfg := requestState.get(client)
+
+	release, err := acquireConcurrencySlot(ctx, cfg.MaxConcurrentRequests)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		if err := waitForHost(ctx, req.URL.Host); err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.RequestTimeoutSec)*time.Second)
+		resp, err = client.Do(req.WithContext(attemptCtx))
+		if err != nil {
+			cancel()
+			if attempt >= cfg.MaxRetries {
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+		} else if attempt >= cfg.MaxRetries || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500) {
+			// attemptCtx also governs reading the body, so it is released
+			// when the caller closes the body rather than here.
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		var wait time.Duration
+		if resp != nil {
+			wait = retryAfter(resp, cfg.RetryBackoffMs)
+			resp.Body.Close()
+		} else {
+			wait = time.Duration(cfg.RetryBackoffMs) * time.Millisecond
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// cancelOnClose releases an attempt's context once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// retryAfter computes the backoff before the next retry, honoring the
+// Retry-After header (seconds or HTTP-date) when present, and otherwise
+// falling back to backoffMs.
+func retryAfter(resp *http.Response, backoffMs int) time.Duration {
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(ra); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+		}
+	}
+	return time.Duration(backoffMs) * time.Millisecond
+}
+
+// GetCtx performs a GET request with standard browser-like headers.
+func GetCtx(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	if err := ValidateURL(rawURL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", requestState.get(client).UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+
+	return Do(ctx, client, req)
+}
+
+// Get performs a GET request with standard browser-like headers.
+func Get(client *http.Client, url string) (*http.Response, error) {
+	return GetCtx(context.Background(), client, url)
+}
+
+// GetJSONCtx performs a GET request with JSON accept header.
+func GetJSONCtx(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	if err := ValidateURL(rawURL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", requestState.get(client).UserAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+
+	resp, err := Do(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, rawURL)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024)) // 10MB limit
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return body, nil
+}
+
+// GetJSON performs a GET request with JSON accept header.
+func GetJSON(client *http.Client, url string) ([]byte, error) {
+	return GetJSONCtx(context.Background(), client, url)
+}
@@ -0,0 +1,54 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithDefaultsFillsZeroFields(t *testing.T) {
+	cfg := NetConfig{ProxyURL: "http://proxy.example.com:8080"}.withDefaults()
+
+	d := DefaultNetConfig()
+	if cfg.RequestTimeoutSec != d.RequestTimeoutSec {
+		t.Errorf("RequestTimeoutSec = %d, want default %d", cfg.RequestTimeoutSec, d.RequestTimeoutSec)
+	}
+	if cfg.MaxRetries != d.MaxRetries {
+		t.Errorf("MaxRetries = %d, want default %d", cfg.MaxRetries, d.MaxRetries)
+	}
+	if cfg.ProxyURL != "http://proxy.example.com:8080" {
+		t.Errorf("ProxyURL = %q, want it preserved", cfg.ProxyURL)
+	}
+}
+
+func TestNewClientAppliesTotalTimeout(t *testing.T) {
+	client := NewClient(NetConfig{TotalTimeoutSec: 5})
+	if client.Timeout != 5*time.Second {
+		t.Errorf("client.Timeout = %v, want 5s", client.Timeout)
+	}
+}
+
+func TestNewClientDisablesTotalTimeout(t *testing.T) {
+	client := NewClient(NetConfig{TotalTimeoutSec: -1})
+	if client.Timeout != 0 {
+		t.Errorf("client.Timeout = %v, want 0 (disabled)", client.Timeout)
+	}
+}
+
+func TestNewClientAppliesProxy(t *testing.T) {
+	client := NewClient(NetConfig{ProxyURL: "http://proxy.example.com:8080"})
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("client.Transport is not *http.Transport")
+	}
+	if transport.Proxy == nil {
+		t.Error("Proxy func not set despite ProxyURL being configured")
+	}
+}
+
+func TestRetryAfterFallsBackToBackoffMs(t *testing.T) {
+	resp := &http.Response{Header: make(http.Header)}
+	if got := retryAfter(resp, 250); got != 250*time.Millisecond {
+		t.Errorf("retryAfter = %v, want 250ms", got)
+	}
+}
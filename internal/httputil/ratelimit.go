@@ -0,0 +1,104 @@
+package httputil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultHostQPS and defaultHostBurst bound requests per host so bursts of
+// episode-link fetches don't get us blocked. Conservative by design.
+const (
+	defaultHostQPS   = 2.0
+	defaultHostBurst = 4
+)
+
+// bucket is a simple token-bucket limiter for a single host.
+type bucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	qps      float64
+	burst    float64
+	lastFill time.Time
+}
+
+func newBucket(qps float64, burst int) *bucket {
+	return &bucket{
+		tokens:   float64(burst),
+		qps:      qps,
+		burst:    float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *bucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.lastFill).Seconds() * b.qps
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.lastFill = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+
+		deficit := 1 - b.tokens
+		wait := time.Duration(deficit/b.qps*1000) * time.Millisecond
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// hostLimiter keys rate-limit buckets by request host (configurable
+// QPS/burst, defaulting to 2 rps/host).
+type hostLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	qps     float64
+	burst   int
+}
+
+var limiter = &hostLimiter{
+	buckets: make(map[string]*bucket),
+	qps:     defaultHostQPS,
+	burst:   defaultHostBurst,
+}
+
+// SetRateLimit configures the QPS and burst applied to every host bucket
+// created from now on. Buckets already created for a host keep their old
+// rate.
+func SetRateLimit(qps float64, burst int) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.qps = qps
+	limiter.burst = burst
+}
+
+func (l *hostLimiter) bucketFor(host string) *bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = newBucket(l.qps, l.burst)
+		l.buckets[host] = b
+	}
+	return b
+}
+
+// waitForHost blocks until a request to host is permitted by the per-host
+// rate limiter, or ctx is cancelled.
+func waitForHost(ctx context.Context, host string) error {
+	return limiter.bucketFor(host).wait(ctx)
+}
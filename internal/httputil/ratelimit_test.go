@@ -0,0 +1,56 @@
+package httputil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBucketAllowsBurstThenThrottles(t *testing.T) {
+	b := newBucket(2, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := b.wait(ctx); err != nil {
+			t.Fatalf("wait() error on burst token %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst tokens took %v, want near-instant", elapsed)
+	}
+
+	start = time.Now()
+	if err := b.wait(ctx); err != nil {
+		t.Fatalf("wait() error after burst exhausted: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("third call returned after %v, want throttled to ~500ms", elapsed)
+	}
+}
+
+func TestBucketRespectsCancellation(t *testing.T) {
+	b := newBucket(0.1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := b.wait(ctx); err != nil {
+		t.Fatalf("first wait() should consume the burst token: %v", err)
+	}
+
+	cancel()
+	if err := b.wait(ctx); err == nil {
+		t.Error("wait() after cancel should return an error")
+	}
+}
+
+func TestWaitForHostUsesSeparateBuckets(t *testing.T) {
+	SetRateLimit(2, 2)
+	ctx := context.Background()
+
+	if err := waitForHost(ctx, "a.example.com"); err != nil {
+		t.Fatalf("waitForHost(a) error: %v", err)
+	}
+	if err := waitForHost(ctx, "b.example.com"); err != nil {
+		t.Fatalf("waitForHost(b) error: %v", err)
+	}
+}
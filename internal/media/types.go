@@ -1,6 +1,8 @@
 // Package media defines shared types for the lobster application.
 package media
 
+import "lobster/internal/metadata"
+
 // MediaType represents whether content is a movie or TV show.
 type MediaType int
 
@@ -26,9 +28,43 @@ type SearchResult struct {
 	Title    string    // Display title
 	Type     MediaType // Movie or TV
 	Year     string    // Release year
+	Duration string    // e.g. "142 m" (movies only)
 	Seasons  int       // Number of seasons (TV only)
 	Episodes int       // Total episodes (TV only)
 	URL      string    // Full URL to the content page
+
+	// Populated by an optional TMDB enrichment pass; zero values if
+	// enrichment is disabled or the title couldn't be matched.
+	TMDBID   int    // TMDB ID
+	Overview string // Synopsis
+	Runtime  int    // Runtime in minutes (movies only)
+
+	// Tags is the release-quality metadata parsed from Title, for sites
+	// whose listing titles carry scene-style tags (resolution, source,
+	// cam/telesync). Zero value on the common case of a clean display
+	// title with nothing to parse; see metadata.Parse.
+	Tags metadata.Release
+}
+
+// ContentDetail holds the extended metadata shown on a content's detail
+// page, beyond what a search/trending listing (SearchResult) already has.
+type ContentDetail struct {
+	Description string   // Synopsis/plot summary
+	Rating      string   // e.g. "7.5" (site's own rating, not TMDB's)
+	Duration    string   // e.g. "142 min"
+	Released    string   // Release date as shown by the provider
+	Country     string   // Country of origin
+	Genre       []string // Genre tags
+	Casts       []string // Cast member names
+
+	// PosterURL is the content's poster image, parsed from .film-poster
+	// img on the detail page. Empty if the page had no poster image.
+	PosterURL string
+
+	// Tags is the release-quality metadata parsed from the content's
+	// title, where available. Zero value if the caller didn't have a
+	// title to parse against.
+	Tags metadata.Release
 }
 
 // Season represents a TV show season.
@@ -39,9 +75,10 @@ type Season struct {
 
 // Episode represents a TV show episode.
 type Episode struct {
-	Number int
-	Title  string
-	ID     string // Provider-specific episode ID
+	Number  int
+	Title   string
+	ID      string // Provider-specific episode ID
+	AirDate string // Air date (YYYY-MM-DD), populated by TMDB enrichment
 }
 
 // Server represents a streaming server option.
@@ -52,9 +89,17 @@ type Server struct {
 
 // Stream contains the resolved streaming URLs.
 type Stream struct {
-	URL       string     // m3u8 or direct video URL
-	Subtitles []Subtitle // Available subtitle tracks
-	Quality   string     // Resolved quality
+	URL         string       // m3u8 or direct video URL
+	Subtitles   []Subtitle   // Available subtitle tracks
+	Quality     string       // Resolved quality
+	Variants    []Variant    // HLS quality variants, if the source was a master playlist
+	AudioTracks []AudioTrack // Alternate audio renditions, if any
+
+	// Tags is the parsed resolution/codec/source/HDR metadata of the
+	// selected source, for callers (e.g. the UI) that want to display what
+	// was actually picked. Zero value if the resolver didn't have a named
+	// source string to parse (e.g. a single-quality HLS master playlist).
+	Tags metadata.Release
 }
 
 // Subtitle represents a subtitle track.
@@ -64,6 +109,23 @@ type Subtitle struct {
 	URL      string // URL to the subtitle file (usually VTT)
 }
 
+// Variant represents a single quality rendition in an HLS master playlist.
+type Variant struct {
+	URL        string  // Resolved URI of the variant (media) playlist
+	Bandwidth  int     // BANDWIDTH attribute, in bits/sec
+	Resolution string  // RESOLUTION attribute, e.g. "1920x1080"
+	Codecs     string  // CODECS attribute
+	FrameRate  float64 // FRAME-RATE attribute, 0 if absent
+}
+
+// AudioTrack represents an alternate audio rendition (#EXT-X-MEDIA:TYPE=AUDIO).
+type AudioTrack struct {
+	Language string // LANGUAGE attribute
+	Label    string // Display label (NAME attribute)
+	URL      string // URI of the audio rendition playlist
+	Default  bool   // DEFAULT=YES
+}
+
 // HistoryEntry represents a single entry in the watch history.
 type HistoryEntry struct {
 	ID       string    // Provider content ID
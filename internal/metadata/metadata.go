@@ -0,0 +1,149 @@
+// Package metadata parses and scores scene-style torrent release names, so
+// the torrent source can pick the best available quality for a title and
+// reject low-quality cam/telesync releases.
+package metadata
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Release is the metadata extracted from a release name, e.g.
+// "Movie.Name.2024.1080p.WEB-DL.DDP5.1.x265-GROUP".
+type Release struct {
+	Resolution string // "2160p", "1080p", "720p", "480p", or "" if unknown
+	Codec      string // "x265", "x264", or "" if unknown
+	Source     string // "BluRay", "WEB-DL", "HDTV", "DVDRip", or "" if unknown
+	Audio      string // "Atmos", "TrueHD", "DDP5.1", "DD5.1", "AAC", or "" if unknown
+	Group      string // trailing "-GROUP" release tag, or "" if absent
+	HDR        bool   // true if the name carries an HDR/Dolby Vision/10-bit tag
+	LowQuality bool   // true if the name contains a cam/telesync/workprint tag
+}
+
+var nonWordPattern = regexp.MustCompile(`[^\w]+`)
+
+// lowQualityTokens are whole-token release tags (case-insensitive) that
+// mark a cam/telesync/workprint rip not worth offering to users.
+var lowQualityTokens = map[string]bool{
+	"CAM": true, "CAMRIP": true, "HDCAM": true,
+	"TS": true, "TSRIP": true, "HDTS": true, "TELESYNC": true,
+	"PDVD": true, "PREDVDRIP": true,
+	"TC": true, "HDTC": true, "TELECINE": true,
+	"WP": true, "WORKPRINT": true,
+}
+
+var resolutionPatterns = []struct {
+	pattern *regexp.Regexp
+	value   string
+}{
+	{regexp.MustCompile(`(?i)2160p|\b4k\b`), "2160p"},
+	{regexp.MustCompile(`(?i)1080p`), "1080p"},
+	{regexp.MustCompile(`(?i)720p`), "720p"},
+	{regexp.MustCompile(`(?i)480p`), "480p"},
+}
+
+var codecPatterns = []struct {
+	pattern *regexp.Regexp
+	value   string
+}{
+	{regexp.MustCompile(`(?i)x265|hevc|h\.?265`), "x265"},
+	{regexp.MustCompile(`(?i)x264|avc|h\.?264`), "x264"},
+}
+
+var sourcePatterns = []struct {
+	pattern *regexp.Regexp
+	value   string
+}{
+	{regexp.MustCompile(`(?i)blu-?ray|bdrip|brrip`), "BluRay"},
+	{regexp.MustCompile(`(?i)web-?dl|webrip`), "WEB-DL"},
+	{regexp.MustCompile(`(?i)hdtv`), "HDTV"},
+	{regexp.MustCompile(`(?i)dvdrip`), "DVDRip"},
+}
+
+var hdrPattern = regexp.MustCompile(`(?i)10bit|10-bit|\bhdr(10)?\+?\b|\bdv\b|dolby[ ._]?vision`)
+
+var audioPatterns = []struct {
+	pattern *regexp.Regexp
+	value   string
+}{
+	{regexp.MustCompile(`(?i)atmos`), "Atmos"},
+	{regexp.MustCompile(`(?i)truehd`), "TrueHD"},
+	{regexp.MustCompile(`(?i)ddp5\.?1|eac3`), "DDP5.1"},
+	{regexp.MustCompile(`(?i)dd5\.?1|ac3`), "DD5.1"},
+	{regexp.MustCompile(`(?i)\baac\b`), "AAC"},
+}
+
+// groupPattern captures the trailing "-GROUP" release tag, e.g.
+// "...x265-GROUP". Requires a leading letter (and at least two characters)
+// so a trailing numeric range (e.g. a resolution like "1920-1080") isn't
+// mistaken for a group.
+var groupPattern = regexp.MustCompile(`-([A-Za-z][A-Za-z0-9]+)$`)
+
+// Parse extracts resolution/codec/source/audio/group metadata from a
+// release name and flags it as LowQuality if it contains a cam/telesync/
+// workprint token, matched whole-token (case-insensitive) after splitting
+// on non-word chars so e.g. "Cameron" in a title doesn't false-positive on
+// "CAM". Any field left unmatched is the zero value; callers should not
+// assume a title that parses to all zero values is bad input — most
+// streaming-site titles (as opposed to scene release names) simply don't
+// carry these tags.
+func Parse(name string) Release {
+	var r Release
+
+	for _, p := range resolutionPatterns {
+		if p.pattern.MatchString(name) {
+			r.Resolution = p.value
+			break
+		}
+	}
+	for _, p := range codecPatterns {
+		if p.pattern.MatchString(name) {
+			r.Codec = p.value
+			break
+		}
+	}
+	for _, p := range sourcePatterns {
+		if p.pattern.MatchString(name) {
+			r.Source = p.value
+			break
+		}
+	}
+
+	for _, p := range audioPatterns {
+		if p.pattern.MatchString(name) {
+			r.Audio = p.value
+			break
+		}
+	}
+
+	if m := groupPattern.FindStringSubmatch(name); m != nil {
+		r.Group = m[1]
+	}
+
+	r.HDR = hdrPattern.MatchString(name)
+
+	for _, tok := range nonWordPattern.Split(name, -1) {
+		if tok == "" {
+			continue
+		}
+		if lowQualityTokens[strings.ToUpper(tok)] {
+			r.LowQuality = true
+			break
+		}
+	}
+
+	return r
+}
+
+var resolutionScore = map[string]int{"2160p": 4, "1080p": 3, "720p": 2, "480p": 1}
+var codecScore = map[string]int{"x265": 2, "x264": 1}
+var sourceScore = map[string]int{"BluRay": 3, "WEB-DL": 2, "HDTV": 1, "DVDRip": 1}
+
+// Score ranks a release for sorting candidates best-first. LowQuality
+// releases always score below any recognized release.
+func Score(r Release) int {
+	if r.LowQuality {
+		return -1
+	}
+	return resolutionScore[r.Resolution]*100 + sourceScore[r.Source]*10 + codecScore[r.Codec]
+}
@@ -0,0 +1,107 @@
+package metadata
+
+import "testing"
+
+func TestParseResolutionCodecSource(t *testing.T) {
+	r := Parse("Movie.Name.2024.1080p.WEB-DL.x265-GROUP")
+	if r.Resolution != "1080p" {
+		t.Errorf("Resolution = %q, want 1080p", r.Resolution)
+	}
+	if r.Codec != "x265" {
+		t.Errorf("Codec = %q, want x265", r.Codec)
+	}
+	if r.Source != "WEB-DL" {
+		t.Errorf("Source = %q, want WEB-DL", r.Source)
+	}
+	if r.LowQuality {
+		t.Error("LowQuality = true, want false")
+	}
+}
+
+func TestParseLowQualityTokens(t *testing.T) {
+	tests := []string{
+		"Movie.Name.2024.CAM.x264-GROUP",
+		"Movie.Name.2024.HDCAM-GROUP",
+		"Movie.Name.2024.TS.x264-GROUP",
+		"Movie.Name.2024.HDTS-GROUP",
+		"Movie.Name.2024.TELESYNC-GROUP",
+		"Movie.Name.2024.WORKPRINT-GROUP",
+	}
+	for _, name := range tests {
+		if r := Parse(name); !r.LowQuality {
+			t.Errorf("Parse(%q).LowQuality = false, want true", name)
+		}
+	}
+}
+
+func TestParseDoesNotFalsePositiveOnSubstrings(t *testing.T) {
+	// "Cameron" contains "cam" as a substring but not as a whole token.
+	r := Parse("Cameron.2024.1080p.BluRay.x264-GROUP")
+	if r.LowQuality {
+		t.Error("LowQuality = true for a title merely containing \"cam\" as a substring")
+	}
+}
+
+func TestParseHDR(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Movie.Name.2024.2160p.BluRay.x265.10bit-GROUP", true},
+		{"Movie.Name.2024.2160p.BluRay.HDR.x265-GROUP", true},
+		{"Movie.Name.2024.2160p.Dolby.Vision.x265-GROUP", true},
+		{"Movie.Name.2024.1080p.WEB-DL.x264-GROUP", false},
+	}
+	for _, tt := range tests {
+		if r := Parse(tt.name); r.HDR != tt.want {
+			t.Errorf("Parse(%q).HDR = %v, want %v", tt.name, r.HDR, tt.want)
+		}
+	}
+}
+
+func TestParseAudio(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Movie.Name.2024.2160p.BluRay.DDP5.1.Atmos.x265-GROUP", "Atmos"},
+		{"Movie.Name.2024.1080p.BluRay.TrueHD.x264-GROUP", "TrueHD"},
+		{"Movie.Name.2024.1080p.WEB-DL.DDP5.1.x264-GROUP", "DDP5.1"},
+		{"Movie.Name.2024.720p.WEB-DL.AAC.x264-GROUP", "AAC"},
+		{"Movie.Name.2024.1080p.WEB-DL.x264-GROUP", ""},
+	}
+	for _, tt := range tests {
+		if r := Parse(tt.name); r.Audio != tt.want {
+			t.Errorf("Parse(%q).Audio = %q, want %q", tt.name, r.Audio, tt.want)
+		}
+	}
+}
+
+func TestParseGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Movie.Name.2024.1080p.WEB-DL.x265-GROUP", "GROUP"},
+		{"Movie.Name.2024.1080p.WEB-DL.x265", ""},
+		{"The.Matrix.1999.1080p.BluRay.x264-RARBG", "RARBG"},
+	}
+	for _, tt := range tests {
+		if r := Parse(tt.name); r.Group != tt.want {
+			t.Errorf("Parse(%q).Group = %q, want %q", tt.name, r.Group, tt.want)
+		}
+	}
+}
+
+func TestScoreOrdersByQuality(t *testing.T) {
+	best := Parse("Movie.2024.2160p.BluRay.x265-GROUP")
+	mid := Parse("Movie.2024.1080p.WEB-DL.x264-GROUP")
+	cam := Parse("Movie.2024.HDCAM-GROUP")
+
+	if Score(best) <= Score(mid) {
+		t.Errorf("Score(2160p BluRay) = %d, want > Score(1080p WEB-DL) = %d", Score(best), Score(mid))
+	}
+	if Score(cam) >= Score(mid) {
+		t.Errorf("Score(cam) = %d, want < Score(mid) = %d", Score(cam), Score(mid))
+	}
+}
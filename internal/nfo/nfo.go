@@ -0,0 +1,172 @@
+// Package nfo writes Kodi/Jellyfin-compatible .nfo sidecar files and
+// poster/fanart images next to a downloaded media file, so a library built
+// from lobster downloads is recognized without a second metadata scrape.
+package nfo
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+// movieXML is the Kodi <movie> schema subset lobster can populate.
+type movieXML struct {
+	XMLName xml.Name   `xml:"movie"`
+	Title   string     `xml:"title"`
+	Plot    string     `xml:"plot,omitempty"`
+	Year    string     `xml:"year,omitempty"`
+	Rating  string     `xml:"rating,omitempty"`
+	Runtime string     `xml:"runtime,omitempty"`
+	Genre   []string   `xml:"genre,omitempty"`
+	Actor   []actorXML `xml:"actor"`
+}
+
+// episodeXML is the Kodi <episodedetails> schema subset, used for TV
+// downloads instead of movieXML. Kodi normally keeps show-level fields
+// (genre, rating) in a separate tvshow.nfo; lobster only downloads one
+// episode at a time, so they're folded into the same file here.
+type episodeXML struct {
+	XMLName xml.Name   `xml:"episodedetails"`
+	Title   string     `xml:"title"`
+	Plot    string     `xml:"plot,omitempty"`
+	Season  int        `xml:"season"`
+	Episode int        `xml:"episode"`
+	Rating  string     `xml:"rating,omitempty"`
+	Genre   []string   `xml:"genre,omitempty"`
+	Actor   []actorXML `xml:"actor"`
+}
+
+type actorXML struct {
+	Name string `xml:"name"`
+}
+
+// Write emits a Kodi-compatible .nfo next to the downloaded file in dir —
+// movie.nfo for a movie, tvshow.nfo with an <episodedetails> root for a TV
+// episode (season/episode from media.Episode) — plus poster.jpg and
+// fanart.jpg fetched from detail.PosterURL when set. Every output path
+// goes through httputil.SafeDownloadPath so the same traversal
+// protections as the video download apply. detail may be nil, in which
+// case only title/year/season/episode are written.
+func Write(ctx context.Context, dir, title, year string, detail *media.ContentDetail, season, episode int) error {
+	var doc interface{}
+	filename := "movie.nfo"
+
+	if season > 0 && episode > 0 {
+		filename = "tvshow.nfo"
+		ep := episodeXML{Title: title, Season: season, Episode: episode}
+		if detail != nil {
+			ep.Plot = detail.Description
+			ep.Rating = detail.Rating
+			ep.Genre = detail.Genre
+			ep.Actor = actorsFromCasts(detail.Casts)
+		}
+		doc = ep
+	} else {
+		m := movieXML{Title: title, Year: year}
+		if detail != nil {
+			m.Plot = detail.Description
+			m.Rating = detail.Rating
+			m.Runtime = runtimeMinutes(detail.Duration)
+			m.Genre = detail.Genre
+			m.Actor = actorsFromCasts(detail.Casts)
+		}
+		doc = m
+	}
+
+	if err := writeXML(dir, filename, doc); err != nil {
+		return err
+	}
+
+	if detail == nil || detail.PosterURL == "" {
+		return nil
+	}
+	if err := downloadImage(ctx, dir, "poster.jpg", detail.PosterURL); err != nil {
+		return fmt.Errorf("downloading poster: %w", err)
+	}
+	// flixhq's detail page exposes only one image, so fanart.jpg reuses it
+	// rather than leaving Kodi's backdrop slot empty.
+	if err := downloadImage(ctx, dir, "fanart.jpg", detail.PosterURL); err != nil {
+		return fmt.Errorf("downloading fanart: %w", err)
+	}
+	return nil
+}
+
+func actorsFromCasts(casts []string) []actorXML {
+	if len(casts) == 0 {
+		return nil
+	}
+	actors := make([]actorXML, len(casts))
+	for i, name := range casts {
+		actors[i] = actorXML{Name: name}
+	}
+	return actors
+}
+
+// runtimeMinutes converts a duration string like "142 min" to Kodi's plain
+// "142"; anything it can't parse is left untouched.
+func runtimeMinutes(duration string) string {
+	fields := strings.Fields(duration)
+	if len(fields) == 0 {
+		return duration
+	}
+	if _, err := strconv.Atoi(fields[0]); err != nil {
+		return duration
+	}
+	return fields[0]
+}
+
+func writeXML(dir, filename string, doc interface{}) error {
+	path, err := httputil.SafeDownloadPath(dir, filename)
+	if err != nil {
+		return fmt.Errorf("resolving %s path: %w", filename, err)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", filename, err)
+	}
+	data = append([]byte(xml.Header), data...)
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+func downloadImage(ctx context.Context, dir, filename, imageURL string) error {
+	if err := httputil.ValidateURL(imageURL); err != nil {
+		return err
+	}
+
+	path, err := httputil.SafeDownloadPath(dir, filename)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+
+	client := httputil.NewClient(httputil.DefaultNetConfig())
+	resp, err := httputil.GetCtx(ctx, client, imageURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
@@ -0,0 +1,32 @@
+package nfo
+
+import "testing"
+
+func TestRuntimeMinutes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"142 min", "142"},
+		{"90", "90"},
+		{"", ""},
+		{"unknown", "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := runtimeMinutes(tt.input); got != tt.want {
+			t.Errorf("runtimeMinutes(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestActorsFromCasts(t *testing.T) {
+	if got := actorsFromCasts(nil); got != nil {
+		t.Errorf("actorsFromCasts(nil) = %v, want nil", got)
+	}
+
+	actors := actorsFromCasts([]string{"Alice", "Bob"})
+	if len(actors) != 2 || actors[0].Name != "Alice" || actors[1].Name != "Bob" {
+		t.Errorf("actorsFromCasts = %v, want [Alice Bob]", actors)
+	}
+}
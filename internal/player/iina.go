@@ -0,0 +1,86 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"lobster/internal/media"
+)
+
+// IINA implements the Player interface for IINA, a macOS player that embeds
+// mpv and accepts mpv options prefixed with --mpv- via its iina-cli wrapper.
+type IINA struct{}
+
+func (i *IINA) Name() string { return "iina" }
+
+func (i *IINA) Available() bool {
+	_, err := exec.LookPath("iina-cli")
+	return err == nil
+}
+
+// Play launches IINA via iina-cli, passing through mpv's own IPC flag so
+// resume position can be read with the same mpvTracker mpv itself uses.
+func (i *IINA) Play(stream *media.Stream, title string, startPos float64, subFile string) (float64, error) {
+	socketDir, err := os.MkdirTemp("", "lobster-iina-*")
+	if err != nil {
+		return 0, fmt.Errorf("creating temp dir for iina socket: %w", err)
+	}
+	defer os.RemoveAll(socketDir)
+
+	socketPath := filepath.Join(socketDir, "socket")
+
+	args := []string{
+		stream.URL,
+		"--mpv-force-media-title=" + title,
+		"--mpv-input-ipc-server=" + socketPath,
+		"--no-stdin",
+	}
+
+	if startPos > 0 {
+		args = append(args, fmt.Sprintf("--mpv-start=+%.0f", startPos))
+	}
+
+	if subFile != "" {
+		args = append(args, "--mpv-sub-file="+subFile)
+	} else {
+		for _, sub := range stream.Subtitles {
+			if sub.URL != "" {
+				args = append(args, "--mpv-sub-file="+sub.URL)
+				break
+			}
+		}
+	}
+
+	cmd := exec.Command("iina-cli", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("starting iina: %w", err)
+	}
+
+	tracker := &mpvTracker{socketPath: socketPath}
+	posCh := make(chan float64, 1)
+	go func() {
+		posCh <- tracker.Track()
+	}()
+
+	// mpvTracker.Track blocks on its IPC socket until iina-cli's embedded
+	// mpv closes it, which happens as part of process exit — so waiting on
+	// posCh here doesn't hang, and it gets us the tracker's true final
+	// position instead of racing its closure-captured write.
+	waitErr := cmd.Wait()
+	lastPos := <-posCh
+
+	if waitErr != nil {
+		if _, ok := waitErr.(*exec.ExitError); ok {
+			return lastPos, nil
+		}
+		return lastPos, fmt.Errorf("running iina: %w", waitErr)
+	}
+
+	return lastPos, nil
+}
@@ -0,0 +1,264 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"lobster/internal/media"
+)
+
+// PlaybackHooks lets a caller wire mpv's in-player keybindings (next/
+// previous episode, force-mark-watched, subtitle cycling) back into the
+// provider/extractor pipeline, without mpv or the IPC session itself
+// knowing anything about providers or episodes.
+type PlaybackHooks struct {
+	// OnNext/OnPrevious resolve and return the stream to queue when the
+	// user presses the bound next/previous-episode key (or when
+	// Controller.Next/Previous is called programmatically). An error
+	// (e.g. "no next episode") leaves playback where it is.
+	OnNext     func() (stream *media.Stream, title string, subFile string, err error)
+	OnPrevious func() (stream *media.Stream, title string, subFile string, err error)
+
+	// OnMarkWatched fires once playback crosses MarkWatchedThreshold
+	// percent, or when the user presses the force-mark-watched key. It
+	// receives mpv's own last-observed position/duration so the caller
+	// doesn't need a second source of truth for them.
+	OnMarkWatched func(position, duration float64)
+
+	// OnCycleSubtitle is called when the user presses the subtitle-cycle
+	// key; it returns the URL of the next subtitle track to load (cycling
+	// through a provider-supplied list), or "" to leave subtitles as-is.
+	OnCycleSubtitle func() string
+
+	// MarkWatchedThreshold is the percent-pos (0-100) at which
+	// OnMarkWatched fires automatically. Zero means use the default (85).
+	MarkWatchedThreshold float64
+}
+
+const defaultMarkWatchedThreshold = 85.0
+
+// Controller drives an already-running mpv instance without restarting
+// it: queueing the next or previous episode into the same process via
+// loadfile append-play, and pushing an additional subtitle track.
+type Controller interface {
+	Next() error
+	Previous() error
+	LoadNextInPlaylist(stream *media.Stream, title string, subFile string) error
+	AddSubtitle(url string) error
+}
+
+// HookablePlayer is implemented by players that support in-player controls
+// over a live IPC channel. Only MPV implements it today; VLC/IINA/Celluloid
+// callers should type-assert before using it.
+type HookablePlayer interface {
+	SetHooks(h *PlaybackHooks)
+}
+
+// keybindings maps the script-message name mpv sends back over the IPC
+// socket to the default key it's bound to.
+var keybindings = []struct {
+	key string
+	msg string
+}{
+	{"Shift+N", "lobster-next"},
+	{"Shift+P", "lobster-prev"},
+	{"w", "lobster-mark-watched"},
+	{"s", "lobster-cycle-sub"},
+}
+
+// observedProperties is the set of properties the session multiplexes
+// over the single IPC connection, each with its own observe_property ID.
+var observedProperties = []struct {
+	id   int
+	name string
+}{
+	{1, "time-pos"},
+	{2, "duration"},
+	{3, "pause"},
+	{4, "eof-reached"},
+	{5, "percent-pos"},
+}
+
+// ipcSession owns mpv's IPC socket for the lifetime of a Play call. It
+// multiplexes property observation (position tracking, auto-mark-watched)
+// and custom keybindings (next/previous episode, force-mark-watched,
+// subtitle cycling) over that one connection, and implements Controller
+// so hook callbacks (and external callers) can queue the next file into
+// the same mpv process.
+type ipcSession struct {
+	socketPath string
+	hooks      *PlaybackHooks
+
+	conn net.Conn
+
+	lastPos       float64
+	duration      float64
+	markedWatched bool
+}
+
+// run dials the socket once it appears, registers property observers and
+// keybindings, then dispatches events until mpv closes the connection. It
+// returns the last known playback position, same contract the old
+// mpvTracker.Track had.
+func (s *ipcSession) run() float64 {
+	for i := 0; i < 50; i++ {
+		if _, err := os.Stat(s.socketPath); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	conn, err := net.Dial("unix", s.socketPath)
+	if err != nil {
+		return 0
+	}
+	defer conn.Close()
+	s.conn = conn
+
+	for _, p := range observedProperties {
+		s.sendCommand([]interface{}{"observe_property", p.id, p.name})
+	}
+	for _, b := range keybindings {
+		s.sendCommand([]interface{}{"keybind", b.key, "script-message " + b.msg})
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		s.handleLine(scanner.Bytes())
+	}
+
+	return s.lastPos
+}
+
+func (s *ipcSession) sendCommand(cmd []interface{}) error {
+	if s.conn == nil {
+		return fmt.Errorf("mpv IPC socket not connected")
+	}
+	data, err := json.Marshal(map[string]interface{}{"command": cmd})
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = s.conn.Write(data)
+	return err
+}
+
+func (s *ipcSession) handleLine(line []byte) {
+	var event struct {
+		Event string          `json:"event"`
+		Name  string          `json:"name"`
+		Data  json.RawMessage `json:"data"`
+		Args  []string        `json:"args"`
+	}
+	if err := json.Unmarshal(line, &event); err != nil {
+		return
+	}
+
+	switch event.Event {
+	case "property-change":
+		s.handlePropertyChange(event.Name, event.Data)
+	case "client-message":
+		if len(event.Args) > 0 {
+			s.handleClientMessage(event.Args[0])
+		}
+	}
+}
+
+func (s *ipcSession) handlePropertyChange(name string, data json.RawMessage) {
+	switch name {
+	case "time-pos":
+		var pos float64
+		if json.Unmarshal(data, &pos) == nil && pos > 0 {
+			s.lastPos = pos
+		}
+	case "duration":
+		var d float64
+		if json.Unmarshal(data, &d) == nil && d > 0 {
+			s.duration = d
+		}
+	case "percent-pos":
+		var pct float64
+		if json.Unmarshal(data, &pct) != nil {
+			return
+		}
+		threshold := defaultMarkWatchedThreshold
+		if s.hooks != nil && s.hooks.MarkWatchedThreshold > 0 {
+			threshold = s.hooks.MarkWatchedThreshold
+		}
+		if !s.markedWatched && pct >= threshold {
+			s.markedWatched = true
+			s.fireMarkWatched()
+		}
+	}
+}
+
+func (s *ipcSession) handleClientMessage(msg string) {
+	switch msg {
+	case "lobster-next":
+		s.Next()
+	case "lobster-prev":
+		s.Previous()
+	case "lobster-mark-watched":
+		s.markedWatched = true
+		s.fireMarkWatched()
+	case "lobster-cycle-sub":
+		if s.hooks != nil && s.hooks.OnCycleSubtitle != nil {
+			if url := s.hooks.OnCycleSubtitle(); url != "" {
+				s.AddSubtitle(url)
+			}
+		}
+	}
+}
+
+func (s *ipcSession) fireMarkWatched() {
+	if s.hooks != nil && s.hooks.OnMarkWatched != nil {
+		s.hooks.OnMarkWatched(s.lastPos, s.duration)
+	}
+}
+
+// Next queues the next episode, as resolved by the OnNext hook, into the
+// running mpv process.
+func (s *ipcSession) Next() error {
+	if s.hooks == nil || s.hooks.OnNext == nil {
+		return fmt.Errorf("no next-episode hook configured")
+	}
+	stream, title, subFile, err := s.hooks.OnNext()
+	if err != nil {
+		return err
+	}
+	return s.LoadNextInPlaylist(stream, title, subFile)
+}
+
+// Previous queues the previous episode, as resolved by the OnPrevious
+// hook, into the running mpv process.
+func (s *ipcSession) Previous() error {
+	if s.hooks == nil || s.hooks.OnPrevious == nil {
+		return fmt.Errorf("no previous-episode hook configured")
+	}
+	stream, title, subFile, err := s.hooks.OnPrevious()
+	if err != nil {
+		return err
+	}
+	return s.LoadNextInPlaylist(stream, title, subFile)
+}
+
+// LoadNextInPlaylist appends stream to mpv's internal playlist and starts
+// playing it immediately, without tearing down the current process (and
+// so without losing the IPC session or its observers/keybindings).
+func (s *ipcSession) LoadNextInPlaylist(stream *media.Stream, title string, subFile string) error {
+	opts := "force-media-title=" + title
+	if subFile != "" {
+		opts += ",sub-file=" + subFile
+	}
+	return s.sendCommand([]interface{}{"loadfile", stream.URL, "append-play", opts})
+}
+
+// AddSubtitle adds url as an extra subtitle track on the currently playing
+// file.
+func (s *ipcSession) AddSubtitle(url string) error {
+	return s.sendCommand([]interface{}{"sub-add", url})
+}
@@ -18,7 +18,9 @@ import (
 // MPV implements the Player interface for mpv.
 // Uses exec.Command with explicit args (no shell interpretation)
 // and IPC via Unix socket at a randomized temp path.
-type MPV struct{}
+type MPV struct {
+	hooks *PlaybackHooks
+}
 
 func (m *MPV) Name() string { return "mpv" }
 
@@ -27,6 +29,13 @@ func (m *MPV) Available() bool {
 	return err == nil
 }
 
+// SetHooks wires next/previous-episode, mark-watched, and subtitle-cycle
+// keybindings to callbacks supplied by the caller. Must be called before
+// Play; implements HookablePlayer.
+func (m *MPV) SetHooks(h *PlaybackHooks) {
+	m.hooks = h
+}
+
 // Play launches mpv with the given stream and returns the final playback position.
 func (m *MPV) Play(stream *media.Stream, title string, startPos float64, subFile string) (float64, error) {
 	// Create randomized IPC socket path (prevents symlink attacks)
@@ -71,15 +80,26 @@ func (m *MPV) Play(stream *media.Stream, title string, startPos float64, subFile
 		return 0, fmt.Errorf("starting mpv: %w", err)
 	}
 
-	// Wait briefly for IPC socket to become available
-	var lastPos float64
+	// The IPC session owns the socket for the life of this process: it
+	// multiplexes position tracking with the next/previous/mark-watched/
+	// subtitle-cycle keybindings below, rather than treating the socket as
+	// the write-once, observe-one-property channel trackPosition used to.
+	session := &ipcSession{socketPath: socketPath, hooks: m.hooks}
+	posCh := make(chan float64, 1)
 	go func() {
-		lastPos = m.trackPosition(socketPath)
+		posCh <- session.run()
 	}()
 
-	if err := cmd.Wait(); err != nil {
+	// ipcSession.run blocks on its IPC socket until mpv closes it, which
+	// happens as part of process exit — so waiting on posCh here doesn't
+	// hang, and it gets us the session's true final position instead of
+	// racing its closure-captured write.
+	waitErr := cmd.Wait()
+	lastPos := <-posCh
+
+	if waitErr != nil {
 		// mpv returns non-zero on user quit, which is normal
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 4 {
+		if exitErr, ok := waitErr.(*exec.ExitError); ok && exitErr.ExitCode() == 4 {
 			return lastPos, nil
 		}
 	}
@@ -87,19 +107,25 @@ func (m *MPV) Play(stream *media.Stream, title string, startPos float64, subFile
 	return lastPos, nil
 }
 
-// trackPosition polls mpv's IPC socket for the current playback position.
-func (m *MPV) trackPosition(socketPath string) float64 {
+// mpvTracker implements PositionTracker over mpv's native JSON IPC socket,
+// observing the time-pos property. IINA embeds mpv and exposes the same
+// socket via --mpv-input-ipc-server, so it reuses this tracker too.
+type mpvTracker struct {
+	socketPath string
+}
+
+func (t *mpvTracker) Track() float64 {
 	var lastPos float64
 
 	// Wait for socket to appear
 	for i := 0; i < 50; i++ {
-		if _, err := os.Stat(socketPath); err == nil {
+		if _, err := os.Stat(t.socketPath); err == nil {
 			break
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	conn, err := net.Dial("unix", socketPath)
+	conn, err := net.Dial("unix", t.socketPath)
 	if err != nil {
 		return 0
 	}
@@ -7,7 +7,11 @@ import (
 	"lobster/internal/media"
 )
 
-// Player is the interface for media player implementations.
+// Player is the interface for media player implementations. Every backend
+// reports a real last-known playback position read from the player's own
+// control interface (mpv's IPC socket, VLC's HTTP interface, IINA's
+// embedded mpv socket, or Celluloid's D-Bus interface) rather than always
+// returning 0.
 type Player interface {
 	// Play starts playback of a stream. Returns the last playback position.
 	Play(stream *media.Stream, title string, startPos float64, subFile string) (float64, error)
@@ -26,8 +30,10 @@ func New(name string) Player {
 		return &MPV{}
 	case "vlc":
 		return &VLC{}
-	case "iina", "celluloid":
-		return &Generic{name: name}
+	case "iina":
+		return &IINA{}
+	case "celluloid":
+		return &Celluloid{}
 	default:
 		return &MPV{} // Default to mpv
 	}
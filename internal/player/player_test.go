@@ -0,0 +1,60 @@
+package player
+
+import "testing"
+
+func TestNewSelectsBackend(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"mpv", "mpv"},
+		{"vlc", "vlc"},
+		{"iina", "iina"},
+		{"celluloid", "celluloid"},
+		{"unknown", "mpv"}, // falls back to mpv
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.name)
+			if p.Name() != tt.want {
+				t.Errorf("New(%q).Name() = %q, want %q", tt.name, p.Name(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"1:30", 90},
+		{"1:02:03", 3723},
+		{"45", 45},
+	}
+
+	for _, tt := range tests {
+		got := parseDuration(tt.input)
+		if got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  string
+	}{
+		{90, "1:30"},
+		{3723, "1:02:03"},
+	}
+
+	for _, tt := range tests {
+		got := formatDuration(tt.input)
+		if got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
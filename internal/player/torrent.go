@@ -0,0 +1,82 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// torrentBinaries is checked in PATH order; webtorrent-cli is preferred
+// over peerflix when both are installed, since it's the more actively
+// maintained of the two.
+var torrentBinaries = []string{"webtorrent", "peerflix"}
+
+// TorrentPlayer streams a magnet URI directly, without an intermediate
+// ffmpeg/extractor stage, by shelling out to an external torrent-to-HTTP
+// streaming bridge found on PATH. webtorrent-cli and peerflix both launch
+// their own player process and report no playback position, so unlike
+// mpv/vlc/iina/celluloid this doesn't implement the Player interface.
+type TorrentPlayer struct {
+	binary string // "" if none of torrentBinaries was found
+}
+
+// NewTorrentPlayer detects the first available torrent-streaming binary
+// on PATH.
+func NewTorrentPlayer() *TorrentPlayer {
+	t := &TorrentPlayer{}
+	for _, bin := range torrentBinaries {
+		if _, err := exec.LookPath(bin); err == nil {
+			t.binary = bin
+			break
+		}
+	}
+	return t
+}
+
+// Available reports whether a supported torrent-streaming binary was found.
+func (t *TorrentPlayer) Available() bool {
+	return t.binary != ""
+}
+
+// PlayMagnet hands magnetURI to the resolved binary, which streams the
+// torrent over HTTP and launches playerName itself.
+func (t *TorrentPlayer) PlayMagnet(ctx context.Context, magnetURI, playerName string) error {
+	if t.binary == "" {
+		return fmt.Errorf("no torrent-streaming binary found in PATH (tried: webtorrent, peerflix)")
+	}
+
+	cmd := exec.CommandContext(ctx, t.binary, magnetURI, "--"+t.playerFlag(playerName))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil // exits non-zero on user close, which is normal
+		}
+		return fmt.Errorf("running %s: %w", t.binary, err)
+	}
+	return nil
+}
+
+// playerFlag maps a configured player name to the resolved binary's
+// launch flag, defaulting to mpv for anything it doesn't know about.
+func (t *TorrentPlayer) playerFlag(playerName string) string {
+	switch t.binary {
+	case "webtorrent":
+		switch playerName {
+		case "mpv", "vlc":
+			return playerName
+		default:
+			return "mpv"
+		}
+	default: // peerflix
+		switch playerName {
+		case "mpv", "vlc", "mplayer", "omx":
+			return playerName
+		default:
+			return "mpv"
+		}
+	}
+}
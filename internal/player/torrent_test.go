@@ -0,0 +1,24 @@
+package player
+
+import "testing"
+
+func TestTorrentPlayerFlag(t *testing.T) {
+	tests := []struct {
+		binary string
+		player string
+		want   string
+	}{
+		{"peerflix", "mpv", "mpv"},
+		{"peerflix", "omx", "omx"},
+		{"peerflix", "unknown", "mpv"},
+		{"webtorrent", "vlc", "vlc"},
+		{"webtorrent", "omx", "mpv"},
+	}
+
+	for _, tt := range tests {
+		tp := &TorrentPlayer{binary: tt.binary}
+		if got := tp.playerFlag(tt.player); got != tt.want {
+			t.Errorf("(%q).playerFlag(%q) = %q, want %q", tt.binary, tt.player, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package player
+
+// PositionTracker polls a running player's own control interface (IPC
+// socket, HTTP status page, D-Bus) for the current playback position. Track
+// blocks, polling until the interface stops responding — which happens
+// when the player process exits — and returns the last position it
+// observed. Callers run Track in a goroutine started right after the
+// player process launches, then read the result once cmd.Wait returns.
+type PositionTracker interface {
+	Track() float64
+}
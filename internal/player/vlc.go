@@ -1,52 +1,180 @@
-package player
-
-import (
-	"fmt"
-	"os"
-	"os/exec"
-
-	"lobster/internal/media"
-)
-
-// VLC implements the Player interface for VLC media player.
-type VLC struct{}
-
-func (v *VLC) Name() string { return "vlc" }
-
-func (v *VLC) Available() bool {
-	_, err := exec.LookPath("vlc")
-	return err == nil
-}
-
-// Play launches VLC. VLC doesn't have IPC position tracking like mpv,
-// so we return 0 for position.
-func (v *VLC) Play(stream *media.Stream, title string, startPos float64, subFile string) (float64, error) {
-	args := []string{
-		stream.URL,
-		"--meta-title", title,
-		"--play-and-exit",
-	}
-
-	if startPos > 0 {
-		args = append(args, fmt.Sprintf("--start-time=%.0f", startPos))
-	}
-
-	if subFile != "" {
-		args = append(args, "--sub-file", subFile)
-	}
-
-	cmd := exec.Command("vlc", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	if err := cmd.Run(); err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			_ = exitErr // VLC exits non-zero on user close
-			return 0, nil
-		}
-		return 0, fmt.Errorf("running vlc: %w", err)
-	}
-
-	return 0, nil
-}
+package player
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"lobster/internal/media"
+)
+
+// VLC implements the Player interface for VLC media player.
+type VLC struct{}
+
+func (v *VLC) Name() string { return "vlc" }
+
+func (v *VLC) Available() bool {
+	_, err := exec.LookPath("vlc")
+	return err == nil
+}
+
+// Play launches VLC with its HTTP control interface enabled on a random
+// local port, protected by a random password, so we can poll it for the
+// real playback position instead of always reporting 0.
+func (v *VLC) Play(stream *media.Stream, title string, startPos float64, subFile string) (float64, error) {
+	port, err := freePort()
+	if err != nil {
+		return 0, fmt.Errorf("picking a port for VLC's HTTP interface: %w", err)
+	}
+
+	password, err := randomToken(16)
+	if err != nil {
+		return 0, fmt.Errorf("generating VLC HTTP password: %w", err)
+	}
+
+	args := []string{
+		stream.URL,
+		"--meta-title", title,
+		"--play-and-exit",
+		"--extraintf=http",
+		fmt.Sprintf("--http-port=%d", port),
+		"--http-password=" + password,
+	}
+
+	if startPos > 0 {
+		args = append(args, fmt.Sprintf("--start-time=%.0f", startPos))
+	}
+
+	if subFile != "" {
+		args = append(args, "--sub-file", subFile)
+	}
+
+	cmd := exec.Command("vlc", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("starting vlc: %w", err)
+	}
+
+	tracker := &vlcTracker{port: port, password: password}
+	done := make(chan struct{})
+	posCh := make(chan float64, 1)
+	go func() {
+		posCh <- tracker.Track(done)
+	}()
+
+	waitErr := cmd.Wait()
+	close(done)
+	lastPos := <-posCh
+
+	if waitErr != nil {
+		if _, ok := waitErr.(*exec.ExitError); ok {
+			// VLC exits non-zero on user close, which is normal.
+			return lastPos, nil
+		}
+		return lastPos, fmt.Errorf("running vlc: %w", waitErr)
+	}
+
+	return lastPos, nil
+}
+
+// vlcStatus models the subset of VLC's /requests/status.xml we care about.
+type vlcStatus struct {
+	Time   float64 `xml:"time"`   // Current position, in seconds
+	Length float64 `xml:"length"` // Total length, in seconds
+}
+
+// vlcTracker polls VLC's HTTP interface (--extraintf=http) for
+// /requests/status.xml. Its Track takes a done channel, so it does not
+// satisfy PositionTracker.
+type vlcTracker struct {
+	port     int
+	password string
+	client   *http.Client
+}
+
+// Track polls until done is closed, which Play does immediately after vlc's
+// process exits so this returns as soon as that happens rather than only
+// after failing a handful of polls against a control port nothing answers
+// on anymore.
+func (t *vlcTracker) Track(done <-chan struct{}) float64 {
+	client := t.client
+	if client == nil {
+		client = &http.Client{Timeout: 2 * time.Second}
+	}
+	statusURL := fmt.Sprintf("http://127.0.0.1:%d/requests/status.xml", t.port)
+
+	var lastPos float64
+	failures := 0
+	for {
+		select {
+		case <-done:
+			return lastPos
+		case <-time.After(time.Second):
+		}
+
+		req, err := http.NewRequest(http.MethodGet, statusURL, nil)
+		if err != nil {
+			return lastPos
+		}
+		req.SetBasicAuth("", t.password)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			// VLC hasn't started its HTTP server yet, or has exited.
+			failures++
+			if failures >= 5 {
+				return lastPos
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		var status vlcStatus
+		if err := xml.Unmarshal(body, &status); err != nil {
+			continue
+		}
+
+		failures = 0
+		if status.Time > 0 {
+			lastPos = status.Time
+		}
+	}
+}
+
+// freePort asks the OS for an unused TCP port by binding to :0 and
+// immediately releasing it. There's an inherent race (another process
+// could grab it before VLC starts), but it's the standard trick and good
+// enough for a short-lived local control interface.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// randomToken returns a random hex string n bytes long, used for VLC's
+// HTTP interface password so it isn't guessable by other local users.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
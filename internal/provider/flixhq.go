@@ -1,237 +1,518 @@
-package provider
-
-import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"strings"
-
-	"github.com/PuerkitoBio/goquery"
-
-	"lobster/internal/httputil"
-	"lobster/internal/media"
-)
-
-// FlixHQ implements the Provider interface for the FlixHQ content source.
-type FlixHQ struct {
-	base   string // e.g., "flixhq.to"
-	client *http.Client
-}
-
-// NewFlixHQ creates a new FlixHQ provider.
-func NewFlixHQ(base string) *FlixHQ {
-	return &FlixHQ{
-		base:   base,
-		client: httputil.NewClient(),
-	}
-}
-
-func (f *FlixHQ) baseURL() string {
-	return "https://" + f.base
-}
-
-// maxSearchPages limits how many pages of search results to fetch.
-const maxSearchPages = 3
-
-// Search returns matching results for a query, fetching multiple pages.
-func (f *FlixHQ) Search(query string) ([]media.SearchResult, error) {
-	encoded := httputil.EncodeQuery(query)
-	baseSearchURL := fmt.Sprintf("%s/search/%s", f.baseURL(), encoded)
-
-	// Fetch first page
-	doc, err := f.fetchDocument(baseSearchURL)
-	if err != nil {
-		return nil, fmt.Errorf("searching for %q: %w", query, err)
-	}
-
-	results := parseSearchResults(doc)
-	lastPage := parseLastPage(doc)
-
-	// Fetch additional pages (up to maxSearchPages)
-	pages := lastPage
-	if pages > maxSearchPages {
-		pages = maxSearchPages
-	}
-	for page := 2; page <= pages; page++ {
-		pageURL := fmt.Sprintf("%s?page=%d", baseSearchURL, page)
-		pageDoc, err := f.fetchDocument(pageURL)
-		if err != nil {
-			break // Stop on error but return what we have
-		}
-		results = append(results, parseSearchResults(pageDoc)...)
-	}
-
-	if len(results) == 0 {
-		return nil, fmt.Errorf("no results found for %q", query)
-	}
-
-	// Set full URLs
-	for i := range results {
-		if !strings.HasPrefix(results[i].URL, "http") {
-			results[i].URL = f.baseURL() + results[i].URL
-		}
-	}
-
-	return results, nil
-}
-
-// GetSeasons returns available seasons for a TV show.
-func (f *FlixHQ) GetSeasons(id string) ([]media.Season, error) {
-	if err := httputil.ValidateID(id); err != nil {
-		return nil, fmt.Errorf("invalid content ID: %w", err)
-	}
-
-	numID := extractNumericID(id)
-	if numID == "" {
-		return nil, fmt.Errorf("cannot extract numeric ID from %q", id)
-	}
-
-	url := fmt.Sprintf("%s/ajax/v2/tv/seasons/%s", f.baseURL(), numID)
-	doc, err := f.fetchDocument(url)
-	if err != nil {
-		return nil, fmt.Errorf("getting seasons: %w", err)
-	}
-
-	return parseSeasons(doc), nil
-}
-
-// GetEpisodes returns episodes for a given season.
-func (f *FlixHQ) GetEpisodes(id string, seasonID string) ([]media.Episode, error) {
-	if err := httputil.ValidateID(seasonID); err != nil {
-		return nil, fmt.Errorf("invalid season ID: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/ajax/v2/season/episodes/%s", f.baseURL(), seasonID)
-	doc, err := f.fetchDocument(url)
-	if err != nil {
-		return nil, fmt.Errorf("getting episodes: %w", err)
-	}
-
-	return parseEpisodes(doc), nil
-}
-
-// GetServers returns available streaming servers for content.
-func (f *FlixHQ) GetServers(id string, episodeID string) ([]media.Server, error) {
-	var url string
-
-	if episodeID != "" {
-		// TV episode
-		if err := httputil.ValidateID(episodeID); err != nil {
-			return nil, fmt.Errorf("invalid episode ID: %w", err)
-		}
-		url = fmt.Sprintf("%s/ajax/v2/episode/servers/%s", f.baseURL(), episodeID)
-	} else {
-		// Movie
-		if err := httputil.ValidateID(id); err != nil {
-			return nil, fmt.Errorf("invalid content ID: %w", err)
-		}
-		numID := extractNumericID(id)
-		if numID == "" {
-			return nil, fmt.Errorf("cannot extract numeric ID from %q", id)
-		}
-		url = fmt.Sprintf("%s/ajax/movie/episodes/%s", f.baseURL(), numID)
-	}
-
-	doc, err := f.fetchDocument(url)
-	if err != nil {
-		return nil, fmt.Errorf("getting servers: %w", err)
-	}
-
-	return parseServers(doc), nil
-}
-
-// GetEmbedURL returns the embed URL for a given server.
-func (f *FlixHQ) GetEmbedURL(serverID string) (string, error) {
-	if err := httputil.ValidateID(serverID); err != nil {
-		return "", fmt.Errorf("invalid server ID: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/ajax/episode/sources/%s", f.baseURL(), serverID)
-	resp, err := httputil.Get(f.client, url)
-	if err != nil {
-		return "", fmt.Errorf("getting embed URL: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status %d for server %s", resp.StatusCode, serverID)
-	}
-
-	// The endpoint returns JSON: {"type":"iframe","link":"https://...","sources":[],"tracks":[],"title":""}
-	var result struct {
-		Link string `json:"link"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("parsing embed response: %w", err)
-	}
-
-	if result.Link == "" {
-		return "", fmt.Errorf("no embed URL found for server %s", serverID)
-	}
-
-	return result.Link, nil
-}
-
-// Trending returns trending content from the /home page.
-func (f *FlixHQ) Trending(mediaType media.MediaType) ([]media.SearchResult, error) {
-	url := fmt.Sprintf("%s/home", f.baseURL())
-
-	doc, err := f.fetchDocument(url)
-	if err != nil {
-		return nil, fmt.Errorf("getting trending: %w", err)
-	}
-
-	results := parseTrendingResults(doc, mediaType)
-	for i := range results {
-		if !strings.HasPrefix(results[i].URL, "http") {
-			results[i].URL = f.baseURL() + results[i].URL
-		}
-	}
-	return results, nil
-}
-
-// Recent returns recently added content from /movie or /tv-show pages.
-func (f *FlixHQ) Recent(mediaType media.MediaType) ([]media.SearchResult, error) {
-	var url string
-	switch mediaType {
-	case media.Movie:
-		url = fmt.Sprintf("%s/movie", f.baseURL())
-	case media.TV:
-		url = fmt.Sprintf("%s/tv-show", f.baseURL())
-	default:
-		url = fmt.Sprintf("%s/movie", f.baseURL())
-	}
-
-	doc, err := f.fetchDocument(url)
-	if err != nil {
-		return nil, fmt.Errorf("getting recent: %w", err)
-	}
-
-	results := parseSearchResults(doc)
-	for i := range results {
-		if !strings.HasPrefix(results[i].URL, "http") {
-			results[i].URL = f.baseURL() + results[i].URL
-		}
-	}
-	return results, nil
-}
-
-// fetchDocument fetches a URL and parses it into a goquery Document.
-func (f *FlixHQ) fetchDocument(url string) (*goquery.Document, error) {
-	resp, err := httputil.Get(f.client, url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("parsing HTML: %w", err)
-	}
-
-	return doc, nil
-}
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"lobster/internal/cache"
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+// Cache TTLs for FlixHQ's parsed responses. Detail/season data rarely
+// changes once published; trending/recent listings and episode/server
+// lists shift as new content is added, so they're kept short-lived.
+const (
+	detailCacheTTL   = 24 * time.Hour
+	seasonCacheTTL   = 24 * time.Hour
+	trendingCacheTTL = 10 * time.Minute
+	recentCacheTTL   = 10 * time.Minute
+	searchCacheTTL   = 10 * time.Minute
+	episodeCacheTTL  = 5 * time.Minute
+	serverCacheTTL   = 5 * time.Minute
+)
+
+// cachingEnabled is a process-wide toggle for --no-cache. It's checked
+// alongside FlixHQ.cache (which can independently be nil if the on-disk
+// store couldn't be opened), so one flag disables caching for every
+// FlixHQ instance without threading a param through the Factory/Registry
+// plumbing.
+var cachingEnabled = true
+
+// SetCachingEnabled turns provider response caching on or off process-wide.
+// Used by --no-cache.
+func SetCachingEnabled(enabled bool) {
+	cachingEnabled = enabled
+}
+
+// FlixHQ implements the Provider interface for the FlixHQ content source.
+type FlixHQ struct {
+	base   string // e.g., "flixhq.to"
+	client *http.Client
+	cache  *cache.Store // nil disables caching, not lookups
+}
+
+// NewFlixHQ creates a new FlixHQ provider.
+func NewFlixHQ(base string) *FlixHQ {
+	store, _ := cache.New("flixhq")
+	return &FlixHQ{
+		base:   base,
+		client: httputil.NewClient(httputil.DefaultNetConfig()),
+		cache:  store,
+	}
+}
+
+// getCached unmarshals a fresh cache entry for key into v, reporting
+// whether one was found.
+func (f *FlixHQ) getCached(key string, v interface{}) bool {
+	if !cachingEnabled || f.cache == nil {
+		return false
+	}
+	entry, ok := f.cache.Get(key)
+	if !ok || !entry.Fresh {
+		return false
+	}
+	return json.Unmarshal(entry.Data, v) == nil
+}
+
+// setCached stores v for key with the given TTL, best-effort.
+func (f *FlixHQ) setCached(key string, v interface{}, ttl time.Duration) {
+	if !cachingEnabled || f.cache == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_ = f.cache.Set(key, data, ttl, "")
+}
+
+func init() {
+	Default.Register("flixhq", func(base string) Provider { return NewFlixHQ(base) })
+}
+
+func (f *FlixHQ) baseURL() string {
+	return "https://" + f.base
+}
+
+// maxSearchPages limits how many pages of search results to fetch.
+const maxSearchPages = 3
+
+// defaultSearchConcurrency bounds how many search pages are fetched at once.
+const defaultSearchConcurrency = 3
+
+// SearchOptions tunes FlixHQ.SearchWithOptions. A zero value falls back to
+// Search's defaults (maxSearchPages pages, defaultSearchConcurrency workers).
+type SearchOptions struct {
+	MaxPages    int // 0 = maxSearchPages
+	Concurrency int // 0 = defaultSearchConcurrency
+}
+
+// Search returns matching results for a query, fetching multiple pages
+// concurrently with the default paging options.
+func (f *FlixHQ) Search(ctx context.Context, query string) ([]media.SearchResult, error) {
+	return f.SearchWithOptions(ctx, query, SearchOptions{})
+}
+
+// SearchPage fetches a single page of search results, 1-indexed, along
+// with the total number of pages available. Unlike Search/SearchWithOptions
+// (which aggregate several pages into one slice), this lets a caller walk
+// the catalog page by page, e.g. for --page or an interactive "Load more".
+func (f *FlixHQ) SearchPage(ctx context.Context, query string, page int) ([]media.SearchResult, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	encoded := httputil.EncodeQuery(query)
+	pageURL := fmt.Sprintf("%s/search/%s", f.baseURL(), encoded)
+	if page > 1 {
+		pageURL = fmt.Sprintf("%s?page=%d", pageURL, page)
+	}
+
+	cacheKey := fmt.Sprintf("flixhq.search.page.%s.%d", query, page)
+	var cached struct {
+		Results  []media.SearchResult
+		LastPage int
+	}
+	if f.getCached(cacheKey, &cached) {
+		return cached.Results, cached.LastPage, nil
+	}
+
+	doc, err := f.fetchDocument(ctx, pageURL)
+	if err != nil {
+		return nil, 0, fmt.Errorf("searching for %q (page %d): %w", query, page, err)
+	}
+
+	results := parseSearchResults(doc)
+	for i := range results {
+		if !strings.HasPrefix(results[i].URL, "http") {
+			results[i].URL = f.baseURL() + results[i].URL
+		}
+	}
+	lastPage := parseLastPage(doc)
+
+	f.setCached(cacheKey, struct {
+		Results  []media.SearchResult
+		LastPage int
+	}{results, lastPage}, searchCacheTTL)
+
+	return results, lastPage, nil
+}
+
+// SearchWithOptions is like Search but lets callers cap how many pages are
+// fetched and how many run at once. ctx cancels the whole search, including
+// any page fetches still in flight.
+func (f *FlixHQ) SearchWithOptions(ctx context.Context, query string, opts SearchOptions) ([]media.SearchResult, error) {
+	maxPages := opts.MaxPages
+	if maxPages <= 0 {
+		maxPages = maxSearchPages
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultSearchConcurrency
+	}
+
+	cacheKey := fmt.Sprintf("flixhq.search.%s.%d", query, maxPages)
+	var cached []media.SearchResult
+	if f.getCached(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	encoded := httputil.EncodeQuery(query)
+	baseSearchURL := fmt.Sprintf("%s/search/%s", f.baseURL(), encoded)
+
+	// Fetch first page; it also tells us how many pages exist in total.
+	doc, err := f.fetchDocument(ctx, baseSearchURL)
+	if err != nil {
+		return nil, fmt.Errorf("searching for %q: %w", query, err)
+	}
+
+	pages := parseLastPage(doc)
+	if pages > maxPages {
+		pages = maxPages
+	}
+	if pages < 1 {
+		pages = 1
+	}
+
+	// byPage holds each page's results keyed by page number, so concurrent
+	// fetches of pages 2..N can be reassembled back into page order
+	// regardless of which one finishes first.
+	byPage := make([][]media.SearchResult, pages+1)
+	byPage[1] = parseSearchResults(doc)
+
+	if pages >= 2 {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, concurrency)
+		var mu sync.Mutex
+
+		for page := 2; page <= pages; page++ {
+			page := page
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-sem }()
+
+				pageURL := fmt.Sprintf("%s?page=%d", baseSearchURL, page)
+				pageDoc, err := f.fetchDocument(ctx, pageURL)
+				if err != nil {
+					return // Best-effort: one bad page shouldn't sink the whole search
+				}
+
+				mu.Lock()
+				byPage[page] = parseSearchResults(pageDoc)
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+	}
+
+	var results []media.SearchResult
+	for page := 1; page <= pages; page++ {
+		results = append(results, byPage[page]...)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results found for %q", query)
+	}
+
+	// Set full URLs
+	for i := range results {
+		if !strings.HasPrefix(results[i].URL, "http") {
+			results[i].URL = f.baseURL() + results[i].URL
+		}
+	}
+
+	f.setCached(cacheKey, results, searchCacheTTL)
+	return results, nil
+}
+
+// GetSeasons returns available seasons for a TV show.
+func (f *FlixHQ) GetSeasons(ctx context.Context, id string) ([]media.Season, error) {
+	if err := httputil.ValidateID(id); err != nil {
+		return nil, fmt.Errorf("invalid content ID: %w", err)
+	}
+
+	numID := extractNumericID(id)
+	if numID == "" {
+		return nil, fmt.Errorf("cannot extract numeric ID from %q", id)
+	}
+
+	cacheKey := "flixhq.seasons." + id
+	var cached []media.Season
+	if f.getCached(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	url := fmt.Sprintf("%s/ajax/v2/tv/seasons/%s", f.baseURL(), numID)
+	doc, err := f.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("getting seasons: %w", err)
+	}
+
+	seasons := parseSeasons(doc)
+	f.setCached(cacheKey, seasons, seasonCacheTTL)
+	return seasons, nil
+}
+
+// GetEpisodes returns episodes for a given season.
+func (f *FlixHQ) GetEpisodes(ctx context.Context, id string, seasonID string) ([]media.Episode, error) {
+	if err := httputil.ValidateID(seasonID); err != nil {
+		return nil, fmt.Errorf("invalid season ID: %w", err)
+	}
+
+	cacheKey := "flixhq.episodes." + seasonID
+	var cached []media.Episode
+	if f.getCached(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	url := fmt.Sprintf("%s/ajax/v2/season/episodes/%s", f.baseURL(), seasonID)
+	doc, err := f.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("getting episodes: %w", err)
+	}
+
+	episodes := parseEpisodes(doc)
+	f.setCached(cacheKey, episodes, episodeCacheTTL)
+	return episodes, nil
+}
+
+// GetServers returns available streaming servers for content.
+func (f *FlixHQ) GetServers(ctx context.Context, id string, episodeID string) ([]media.Server, error) {
+	var url string
+
+	if episodeID != "" {
+		// TV episode
+		if err := httputil.ValidateID(episodeID); err != nil {
+			return nil, fmt.Errorf("invalid episode ID: %w", err)
+		}
+		url = fmt.Sprintf("%s/ajax/v2/episode/servers/%s", f.baseURL(), episodeID)
+	} else {
+		// Movie
+		if err := httputil.ValidateID(id); err != nil {
+			return nil, fmt.Errorf("invalid content ID: %w", err)
+		}
+		numID := extractNumericID(id)
+		if numID == "" {
+			return nil, fmt.Errorf("cannot extract numeric ID from %q", id)
+		}
+		url = fmt.Sprintf("%s/ajax/movie/episodes/%s", f.baseURL(), numID)
+	}
+
+	cacheKey := fmt.Sprintf("flixhq.servers.%s.%s", id, episodeID)
+	var cached []media.Server
+	if f.getCached(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	doc, err := f.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("getting servers: %w", err)
+	}
+
+	servers := parseServers(doc)
+	f.setCached(cacheKey, servers, serverCacheTTL)
+	return servers, nil
+}
+
+// GetEmbedURL returns the embed URL for a given server.
+func (f *FlixHQ) GetEmbedURL(ctx context.Context, serverID string) (string, error) {
+	if err := httputil.ValidateID(serverID); err != nil {
+		return "", fmt.Errorf("invalid server ID: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/ajax/episode/sources/%s", f.baseURL(), serverID)
+	resp, err := httputil.GetCtx(ctx, f.client, url)
+	if err != nil {
+		return "", fmt.Errorf("getting embed URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d for server %s", resp.StatusCode, serverID)
+	}
+
+	// The endpoint returns JSON: {"type":"iframe","link":"https://...","sources":[],"tracks":[],"title":""}
+	var result struct {
+		Link string `json:"link"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("parsing embed response: %w", err)
+	}
+
+	if result.Link == "" {
+		return "", fmt.Errorf("no embed URL found for server %s", serverID)
+	}
+
+	return result.Link, nil
+}
+
+// Trending returns trending content from the /home page.
+func (f *FlixHQ) Trending(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error) {
+	cacheKey := fmt.Sprintf("flixhq.trending.%s", mediaType)
+	var cached []media.SearchResult
+	if f.getCached(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	url := fmt.Sprintf("%s/home", f.baseURL())
+
+	doc, err := f.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("getting trending: %w", err)
+	}
+
+	results := parseTrendingResults(doc, mediaType)
+	for i := range results {
+		if !strings.HasPrefix(results[i].URL, "http") {
+			results[i].URL = f.baseURL() + results[i].URL
+		}
+	}
+	f.setCached(cacheKey, results, trendingCacheTTL)
+	return results, nil
+}
+
+// recentListingURL returns the /movie or /tv-show listing page for
+// mediaType, defaulting to /movie for an unrecognized type.
+func (f *FlixHQ) recentListingURL(mediaType media.MediaType) string {
+	switch mediaType {
+	case media.TV:
+		return fmt.Sprintf("%s/tv-show", f.baseURL())
+	default:
+		return fmt.Sprintf("%s/movie", f.baseURL())
+	}
+}
+
+// Recent returns recently added content from /movie or /tv-show pages.
+func (f *FlixHQ) Recent(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error) {
+	url := f.recentListingURL(mediaType)
+
+	cacheKey := fmt.Sprintf("flixhq.recent.%s", mediaType)
+	var cached []media.SearchResult
+	if f.getCached(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	doc, err := f.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("getting recent: %w", err)
+	}
+
+	results := parseSearchResults(doc)
+	for i := range results {
+		if !strings.HasPrefix(results[i].URL, "http") {
+			results[i].URL = f.baseURL() + results[i].URL
+		}
+	}
+	f.setCached(cacheKey, results, recentCacheTTL)
+	return results, nil
+}
+
+// RecentPage fetches a single page of recently-added content, 1-indexed,
+// along with the total number of pages available.
+func (f *FlixHQ) RecentPage(ctx context.Context, mediaType media.MediaType, page int) ([]media.SearchResult, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	url := f.recentListingURL(mediaType)
+	if page > 1 {
+		url = fmt.Sprintf("%s?page=%d", url, page)
+	}
+
+	cacheKey := fmt.Sprintf("flixhq.recent.page.%s.%d", mediaType, page)
+	var cached struct {
+		Results  []media.SearchResult
+		LastPage int
+	}
+	if f.getCached(cacheKey, &cached) {
+		return cached.Results, cached.LastPage, nil
+	}
+
+	doc, err := f.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting recent (page %d): %w", page, err)
+	}
+
+	results := parseSearchResults(doc)
+	for i := range results {
+		if !strings.HasPrefix(results[i].URL, "http") {
+			results[i].URL = f.baseURL() + results[i].URL
+		}
+	}
+	lastPage := parseLastPage(doc)
+
+	f.setCached(cacheKey, struct {
+		Results  []media.SearchResult
+		LastPage int
+	}{results, lastPage}, recentCacheTTL)
+
+	return results, lastPage, nil
+}
+
+// GetDetails returns detailed metadata for a content item's detail page.
+func (f *FlixHQ) GetDetails(ctx context.Context, id string) (*media.ContentDetail, error) {
+	if err := httputil.ValidateID(id); err != nil {
+		return nil, fmt.Errorf("invalid content ID: %w", err)
+	}
+
+	cacheKey := "flixhq.detail." + id
+	var cached media.ContentDetail
+	if f.getCached(cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	url := fmt.Sprintf("%s/%s", f.baseURL(), id)
+	doc, err := f.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("getting details: %w", err)
+	}
+
+	detail := parseDetailPage(doc)
+	f.setCached(cacheKey, detail, detailCacheTTL)
+	return detail, nil
+}
+
+// fetchDocument fetches a URL and parses it into a goquery Document.
+func (f *FlixHQ) fetchDocument(ctx context.Context, url string) (*goquery.Document, error) {
+	resp, err := httputil.GetCtx(ctx, f.client, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+// HiAnime implements the Provider interface for the HiAnime (aniwatch)
+// content source. Unlike FlixHQ, HiAnime has no season concept: a show is
+// just a flat episode list, and sub/dub is a property of the streaming
+// server rather than of the content itself. GetSeasons returns a single
+// synthetic season so the rest of the season/episode selection flow (cmd's
+// resolveAndPlay, the watchlist package) doesn't need a special case.
+type HiAnime struct {
+	base   string // e.g., "hianime.to"
+	client *http.Client
+}
+
+// NewHiAnime creates a new HiAnime provider.
+func NewHiAnime(base string) *HiAnime {
+	return &HiAnime{
+		base:   base,
+		client: httputil.NewClient(httputil.DefaultNetConfig()),
+	}
+}
+
+func init() {
+	Default.Register("hianime", func(base string) Provider { return NewHiAnime(base) })
+}
+
+func (h *HiAnime) baseURL() string {
+	return "https://" + h.base
+}
+
+// Search returns matching anime for a query.
+func (h *HiAnime) Search(ctx context.Context, query string) ([]media.SearchResult, error) {
+	searchURL := fmt.Sprintf("%s/search?keyword=%s", h.baseURL(), httputil.EncodeQuery(query))
+
+	doc, err := h.fetchDocument(ctx, searchURL)
+	if err != nil {
+		return nil, fmt.Errorf("searching for %q: %w", query, err)
+	}
+
+	results := parseHiAnimeResults(doc, ".film_list-wrap .flw-item")
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results found for %q", query)
+	}
+
+	return results, nil
+}
+
+// GetDetails returns detailed metadata for an anime.
+func (h *HiAnime) GetDetails(ctx context.Context, id string) (*media.ContentDetail, error) {
+	if err := httputil.ValidateID(id); err != nil {
+		return nil, fmt.Errorf("invalid content ID: %w", err)
+	}
+
+	doc, err := h.fetchDocument(ctx, fmt.Sprintf("%s/%s", h.baseURL(), id))
+	if err != nil {
+		return nil, fmt.Errorf("getting details: %w", err)
+	}
+
+	return parseDetailPage(doc), nil
+}
+
+// GetSeasons returns a single synthetic season: HiAnime has no season
+// grouping, so the real work happens in GetEpisodes against id directly.
+func (h *HiAnime) GetSeasons(ctx context.Context, id string) ([]media.Season, error) {
+	if err := httputil.ValidateID(id); err != nil {
+		return nil, fmt.Errorf("invalid content ID: %w", err)
+	}
+	return []media.Season{{Number: 1, ID: id}}, nil
+}
+
+// GetEpisodes returns every episode for the anime identified by seasonID
+// (really the content ID, passed through from the synthetic season above).
+func (h *HiAnime) GetEpisodes(ctx context.Context, id string, seasonID string) ([]media.Episode, error) {
+	if err := httputil.ValidateID(seasonID); err != nil {
+		return nil, fmt.Errorf("invalid content ID: %w", err)
+	}
+
+	numID := extractNumericID(seasonID)
+	if numID == "" {
+		return nil, fmt.Errorf("cannot extract numeric ID from %q", seasonID)
+	}
+
+	ajaxURL := fmt.Sprintf("%s/ajax/v2/episode/list/%s", h.baseURL(), numID)
+	body, err := h.fetchAjaxHTML(ctx, ajaxURL)
+	if err != nil {
+		return nil, fmt.Errorf("getting episodes: %w", err)
+	}
+
+	return parseHiAnimeEpisodes(body), nil
+}
+
+// GetServers returns available sub and dub streaming servers for an
+// episode. episodeID is always non-empty for HiAnime, since every unit of
+// content is episode-shaped (even single-episode movies/OVAs).
+func (h *HiAnime) GetServers(ctx context.Context, id string, episodeID string) ([]media.Server, error) {
+	if err := httputil.ValidateID(episodeID); err != nil {
+		return nil, fmt.Errorf("invalid episode ID: %w", err)
+	}
+
+	ajaxURL := fmt.Sprintf("%s/ajax/v2/episode/servers?episodeId=%s", h.baseURL(), extractNumericID(episodeID))
+	body, err := h.fetchAjaxHTML(ctx, ajaxURL)
+	if err != nil {
+		return nil, fmt.Errorf("getting servers: %w", err)
+	}
+
+	return parseHiAnimeServers(body), nil
+}
+
+// GetEmbedURL returns the embed URL for a given server, the same
+// getSources shape FlixHQ uses.
+func (h *HiAnime) GetEmbedURL(ctx context.Context, serverID string) (string, error) {
+	if err := httputil.ValidateID(serverID); err != nil {
+		return "", fmt.Errorf("invalid server ID: %w", err)
+	}
+
+	ajaxURL := fmt.Sprintf("%s/ajax/v2/episode/sources?id=%s", h.baseURL(), extractNumericID(serverID))
+	resp, err := httputil.GetCtx(ctx, h.client, ajaxURL)
+	if err != nil {
+		return "", fmt.Errorf("getting embed URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d for server %s", resp.StatusCode, serverID)
+	}
+
+	var result struct {
+		Link string `json:"link"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("parsing embed response: %w", err)
+	}
+
+	if result.Link == "" {
+		return "", fmt.Errorf("no embed URL found for server %s", serverID)
+	}
+
+	return result.Link, nil
+}
+
+// Trending returns trending anime from the /home page.
+func (h *HiAnime) Trending(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error) {
+	doc, err := h.fetchDocument(ctx, fmt.Sprintf("%s/home", h.baseURL()))
+	if err != nil {
+		return nil, fmt.Errorf("getting trending: %w", err)
+	}
+	return parseHiAnimeResults(doc, "#trending-home .flw-item"), nil
+}
+
+// Recent returns recently updated anime. mediaType is ignored: HiAnime's
+// recently-updated list doesn't split by movie/TV the way FlixHQ's does.
+func (h *HiAnime) Recent(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error) {
+	doc, err := h.fetchDocument(ctx, fmt.Sprintf("%s/recently-updated", h.baseURL()))
+	if err != nil {
+		return nil, fmt.Errorf("getting recent: %w", err)
+	}
+	return parseHiAnimeResults(doc, ".film_list-wrap .flw-item"), nil
+}
+
+// fetchDocument fetches a URL and parses it into a goquery Document.
+func (h *HiAnime) fetchDocument(ctx context.Context, url string) (*goquery.Document, error) {
+	resp, err := httputil.GetCtx(ctx, h.client, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	return doc, nil
+}
+
+// fetchAjaxHTML fetches one of HiAnime's ajax endpoints, which return
+// {"status":true,"html":"<...>"} rather than a full page, and returns the
+// embedded HTML fragment.
+func (h *HiAnime) fetchAjaxHTML(ctx context.Context, ajaxURL string) (string, error) {
+	body, err := httputil.GetJSONCtx(ctx, h.client, ajaxURL)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		HTML string `json:"html"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("parsing ajax response: %w", err)
+	}
+
+	return result.HTML, nil
+}
+
+// parseHiAnimeResults extracts search/trending results matching selector
+// from a goquery document. HiAnime's film-card markup is close enough to
+// FlixHQ's that the same class names apply, but the EPS/SS metadata spans
+// FlixHQ uses aren't present, so only title/URL/type/year are filled in.
+func parseHiAnimeResults(doc *goquery.Document, selector string) []media.SearchResult {
+	var results []media.SearchResult
+
+	doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+		result := media.SearchResult{Type: media.TV}
+
+		link := s.Find(".film-name a, .dynamic-name")
+		result.Title = strings.TrimSpace(link.Text())
+		href, exists := link.Attr("href")
+		if exists {
+			result.URL = href
+			result.ID = extractID(href)
+		}
+
+		if year := strings.TrimSpace(s.Find(".fdi-item").First().Text()); len(year) == 4 {
+			if _, err := strconv.Atoi(year); err == nil {
+				result.Year = year
+			}
+		}
+
+		if result.Title != "" {
+			results = append(results, result)
+		}
+	})
+
+	return results
+}
+
+// parseHiAnimeEpisodes extracts the episode list from the
+// /ajax/v2/episode/list/{id} HTML fragment.
+func parseHiAnimeEpisodes(fragment string) []media.Episode {
+	var episodes []media.Episode
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return nil
+	}
+
+	doc.Find("a.ssl-item.ep-item").Each(func(_ int, s *goquery.Selection) {
+		dataID, exists := s.Attr("data-id")
+		if !exists {
+			return
+		}
+
+		num := 0
+		if n, err := strconv.Atoi(s.AttrOr("data-number", "")); err == nil {
+			num = n
+		}
+
+		title := strings.TrimSpace(s.Find(".ep-name").AttrOr("title", ""))
+		if title == "" {
+			title = strings.TrimSpace(s.Find(".ep-name").Text())
+		}
+
+		episodes = append(episodes, media.Episode{
+			Number: num,
+			Title:  title,
+			ID:     dataID,
+		})
+	})
+
+	return episodes
+}
+
+// parseHiAnimeServers extracts sub and dub server options from the
+// /ajax/v2/episode/servers HTML fragment, labeling each with its audio
+// track so cfg.Provider / --provider can select e.g. "HD-1 (SUB)".
+func parseHiAnimeServers(fragment string) []media.Server {
+	var servers []media.Server
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return nil
+	}
+
+	parseGroup := func(containerID, label string) {
+		doc.Find("#" + containerID).Find(".server-item").Each(func(_ int, s *goquery.Selection) {
+			dataID, exists := s.Attr("data-id")
+			if !exists {
+				return
+			}
+			name := strings.TrimSpace(s.Find(".server-name, a").Text())
+			if name == "" {
+				name = strings.TrimSpace(s.Text())
+			}
+			servers = append(servers, media.Server{
+				Name: fmt.Sprintf("%s (%s)", name, label),
+				ID:   dataID,
+			})
+		})
+	}
+
+	parseGroup("servers-sub", "SUB")
+	parseGroup("servers-dub", "DUB")
+
+	return servers
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 
 	"lobster/internal/media"
+	"lobster/internal/metadata"
 )
 
 // parseSearchResults extracts search results from a goquery document.
@@ -53,6 +54,7 @@ func parseSearchResults(doc *goquery.Document) []media.SearchResult {
 		})
 
 		if result.Title != "" {
+			result.Tags = metadata.Parse(result.Title)
 			results = append(results, result)
 		}
 	})
@@ -169,6 +171,16 @@ func parseDetailPage(doc *goquery.Document) *media.ContentDetail {
 	// Description
 	detail.Description = strings.TrimSpace(doc.Find(".description").First().Text())
 
+	// Poster image: lazy-loaded via data-src, falling back to src for
+	// pages that render it eagerly.
+	if poster := doc.Find(".film-poster img").First(); poster.Length() > 0 {
+		if src, ok := poster.Attr("data-src"); ok && src != "" {
+			detail.PosterURL = src
+		} else if src, ok := poster.Attr("src"); ok {
+			detail.PosterURL = src
+		}
+	}
+
 	// Rating and duration from .stats spans
 	doc.Find(".stats .item").Each(func(_ int, s *goquery.Selection) {
 		text := strings.TrimSpace(s.Text())
@@ -312,6 +324,7 @@ func parseTrendingResults(doc *goquery.Document, mediaType media.MediaType) []me
 		})
 
 		if result.Title != "" {
+			result.Tags = metadata.Parse(result.Title)
 			results = append(results, result)
 		}
 	})
@@ -345,3 +358,18 @@ func FormatDisplayTitle(r media.SearchResult) string {
 	}
 	return strings.Join(parts, " ")
 }
+
+// FormatEpisodeTitle creates a display string for an episode in fzf
+// selection, e.g. "Show — S02E05 · Episode Title (2019-04-14)". The
+// episode title and air date are omitted when not known (no TMDB
+// enrichment configured, or the show/episode wasn't matched).
+func FormatEpisodeTitle(showTitle string, season int, ep media.Episode) string {
+	line := fmt.Sprintf("%s — S%02dE%02d", showTitle, season, ep.Number)
+	if ep.Title != "" {
+		line += " · " + ep.Title
+	}
+	if ep.AirDate != "" {
+		line += fmt.Sprintf(" (%s)", ep.AirDate)
+	}
+	return line
+}
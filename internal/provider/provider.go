@@ -3,33 +3,53 @@
 package provider
 
 import (
+	"context"
+
 	"lobster/internal/media"
 )
 
-// Provider is the interface that content providers must implement.
+// Provider is the interface that content providers must implement. Every
+// method takes a context.Context so a slow provider page can be cancelled
+// when the user aborts an fzf selection.
 type Provider interface {
 	// Search returns matching results for a query.
-	Search(query string) ([]media.SearchResult, error)
+	Search(ctx context.Context, query string) ([]media.SearchResult, error)
 
 	// GetDetails returns detailed metadata for a content item.
-	GetDetails(id string) (*media.ContentDetail, error)
+	GetDetails(ctx context.Context, id string) (*media.ContentDetail, error)
 
 	// GetSeasons returns available seasons for a TV show.
-	GetSeasons(id string) ([]media.Season, error)
+	GetSeasons(ctx context.Context, id string) ([]media.Season, error)
 
 	// GetEpisodes returns episodes for a given season.
-	GetEpisodes(id string, seasonID string) ([]media.Episode, error)
+	GetEpisodes(ctx context.Context, id string, seasonID string) ([]media.Episode, error)
 
 	// GetServers returns available streaming servers.
 	// For movies, episodeID is empty.
-	GetServers(id string, episodeID string) ([]media.Server, error)
+	GetServers(ctx context.Context, id string, episodeID string) ([]media.Server, error)
 
 	// GetEmbedURL returns the embed URL for a given server.
-	GetEmbedURL(serverID string) (string, error)
+	GetEmbedURL(ctx context.Context, serverID string) (string, error)
 
 	// Trending returns trending content.
-	Trending(mediaType media.MediaType) ([]media.SearchResult, error)
+	Trending(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error)
 
 	// Recent returns recently added content.
-	Recent(mediaType media.MediaType) ([]media.SearchResult, error)
+	Recent(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error)
+}
+
+// Pager is implemented by providers whose listing pages support fetching
+// one page at a time, rather than only the aggregated multi-page results
+// Search/Recent return by default. cmd type-asserts for this to support
+// --page and the interactive "Load more" selection flow; providers that
+// don't implement it (e.g. HiAnime, whose listings aren't paginated the
+// same way) just show the default single aggregated result set.
+type Pager interface {
+	// SearchPage returns a single page of search results and the total
+	// number of pages available, 1-indexed.
+	SearchPage(ctx context.Context, query string, page int) (results []media.SearchResult, lastPage int, err error)
+
+	// RecentPage returns a single page of recently-added content and the
+	// total number of pages available, 1-indexed.
+	RecentPage(ctx context.Context, mediaType media.MediaType, page int) (results []media.SearchResult, lastPage int, err error)
 }
@@ -0,0 +1,35 @@
+package provider
+
+import "fmt"
+
+// Factory constructs a Provider for the given base host.
+type Factory func(base string) Provider
+
+// Registry maps provider names (e.g. "flixhq") to factories, so alternative
+// content sources can be plugged in without cmd hardcoding one.
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Default is the process-wide registry, populated by each provider's
+// init() function.
+var Default = NewRegistry()
+
+// Register adds factory under name, overwriting any existing entry.
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[name] = factory
+}
+
+// New constructs the named provider for base.
+func (r *Registry) New(name, base string) (Provider, error) {
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+	return factory(base), nil
+}
@@ -0,0 +1,140 @@
+// Package torznab adapts a Torznab-compatible indexer (internal/torrent)
+// to the provider.Provider interface, so torrent results flow through the
+// same search/select/play pipeline as flixhq/hianime instead of the
+// separate --source torrent bypass that hands a magnet straight to an
+// external torrent-streaming binary (webtorrent or peerflix).
+package torznab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"lobster/internal/media"
+	"lobster/internal/provider"
+	"lobster/internal/quality"
+	"lobster/internal/torrent"
+)
+
+// Torznab adapts a single Torznab indexer to provider.Provider. Every
+// search result is already a specific release (no embed page, no season
+// hierarchy), so GetSeasons/GetEpisodes/GetDetails are deliberately
+// unsupported: Search always reports media.Movie, which keeps
+// resolveAndPlay's TV branch out of the way entirely.
+type Torznab struct {
+	client *torrent.Client
+}
+
+// apiKey is set by cmd from the [torznab] config section before the
+// registry constructs a Torznab instance. provider.Factory only threads a
+// single base-URL string through (see provider.Registry.New), so there's
+// no other way to hand the API key along with it.
+var apiKey string
+
+// SetAPIKey configures the API key used by Torznab instances constructed
+// afterwards.
+func SetAPIKey(key string) {
+	apiKey = key
+}
+
+// New creates a Torznab provider rooted at the given indexer base URL.
+func New(base string) *Torznab {
+	return &Torznab{client: torrent.New(base, apiKey)}
+}
+
+func init() {
+	provider.Default.Register("torznab", func(base string) provider.Provider { return New(base) })
+}
+
+// Search queries the indexer for query and reports the whole match set as
+// a single SearchResult; GetServers re-queries and expands it into the
+// individual magnet candidates.
+func (t *Torznab) Search(ctx context.Context, query string) ([]media.SearchResult, error) {
+	if _, err := t.client.Search(ctx, torrent.SearchParams{Query: query}); err != nil {
+		return nil, err
+	}
+	return []media.SearchResult{{
+		ID:    query,
+		Title: query,
+		Type:  media.Movie,
+		URL:   query,
+	}}, nil
+}
+
+// GetDetails is unsupported: Torznab indexers don't expose a detail page
+// beyond what Search/GetServers already surface.
+func (t *Torznab) GetDetails(ctx context.Context, id string) (*media.ContentDetail, error) {
+	return nil, fmt.Errorf("torznab: detail pages not supported")
+}
+
+// GetSeasons is unsupported: every search result is already a specific
+// release, not a show with a season hierarchy.
+func (t *Torznab) GetSeasons(ctx context.Context, id string) ([]media.Season, error) {
+	return nil, fmt.Errorf("torznab: season browsing not supported; include the season/episode in the search query instead")
+}
+
+// GetEpisodes is unsupported for the same reason as GetSeasons.
+func (t *Torznab) GetEpisodes(ctx context.Context, id string, seasonID string) ([]media.Episode, error) {
+	return nil, fmt.Errorf("torznab: episode browsing not supported; include the season/episode in the search query instead")
+}
+
+// GetServers re-queries the indexer for id (the original search query) and
+// returns every surviving release as a Server, magnet URI as ID, sorted by
+// quality.Rank (under the process-wide --min-quality/--prefer-codec/--no-cam
+// preferences) then seeder count, so the best candidate is first in the
+// fzf picker. A release rejected by those preferences is dropped entirely
+// rather than merely sorted last, since there's no separate filter pass for
+// torznab results the way cmd.filterResults covers embed-based providers.
+func (t *Torznab) GetServers(ctx context.Context, id string, episodeID string) ([]media.Server, error) {
+	results, err := t.client.Search(ctx, torrent.SearchParams{Query: id})
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := quality.Current()
+	filtered := results[:0]
+	for _, r := range results {
+		if quality.Rank(r.Release, prefs) >= 0 {
+			filtered = append(filtered, r)
+		}
+	}
+	results = filtered
+
+	sort.SliceStable(results, func(i, j int) bool {
+		ri, rj := quality.Rank(results[i].Release, prefs), quality.Rank(results[j].Release, prefs)
+		if ri != rj {
+			return ri > rj
+		}
+		return results[i].Seeders > results[j].Seeders
+	})
+
+	servers := make([]media.Server, len(results))
+	for i, r := range results {
+		resolution := r.Release.Resolution
+		if resolution == "" {
+			resolution = "unknown"
+		}
+		servers[i] = media.Server{
+			Name: fmt.Sprintf("%s (%s, %d seeders)", r.Title, resolution, r.Seeders),
+			ID:   r.MagnetURI,
+		}
+	}
+	return servers, nil
+}
+
+// GetEmbedURL returns serverID unchanged: GetServers already set it to the
+// server's magnet URI, and there's no embed page to resolve it from.
+func (t *Torznab) GetEmbedURL(ctx context.Context, serverID string) (string, error) {
+	return serverID, nil
+}
+
+// Trending is unsupported: Torznab indexers only expose search, not a
+// curated listing.
+func (t *Torznab) Trending(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error) {
+	return nil, fmt.Errorf("torznab: trending not supported")
+}
+
+// Recent is unsupported for the same reason as Trending.
+func (t *Torznab) Recent(ctx context.Context, mediaType media.MediaType) ([]media.SearchResult, error) {
+	return nil, fmt.Errorf("torznab: recent not supported")
+}
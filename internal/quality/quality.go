@@ -0,0 +1,95 @@
+// Package quality ranks parsed release metadata (internal/metadata) against
+// a caller's stated preferences, so code with several equivalent
+// candidates — alternate torrent releases, alternate streaming servers —
+// can auto-pick the best one instead of always taking the first.
+package quality
+
+import (
+	"strconv"
+	"strings"
+
+	"lobster/internal/metadata"
+)
+
+// Prefs narrows metadata.Score's fixed ranking with caller-specified
+// overrides: a minimum resolution floor, a preferred codec tiebreaker, and
+// whether to reject cam/telesync releases outright.
+type Prefs struct {
+	MinResolution string // e.g. "1080p"; "" accepts any resolution
+	PreferCodec   string // e.g. "x265"; "" applies no codec preference
+	NoCam         bool   // reject metadata.Release.LowQuality candidates outright
+}
+
+// current holds the process-wide preferences set by cmd from
+// --min-quality/--prefer-codec/--no-cam, read by packages (e.g.
+// provider/torznab) that rank candidates without a direct line to cmd's
+// flag variables.
+var current Prefs
+
+// SetPrefs configures the preferences Current returns. Called once from
+// cmd's flag-parsing setup.
+func SetPrefs(p Prefs) {
+	current = p
+}
+
+// Current returns the preferences most recently set by SetPrefs, or the
+// zero Prefs (no filtering/preference) if it was never called.
+func Current() Prefs {
+	return current
+}
+
+// codecBonus is added to metadata.Score's result when a release's codec
+// matches Prefs.PreferCodec, so it wins ties against an otherwise
+// equally-scored release with a different (or unrecognized) codec.
+const codecBonus = 5
+
+// Rank scores r for sorting best-first under prefs. A release rejected by
+// prefs (below MinResolution, or a cam/telesync release under NoCam) scores
+// -1, the same sentinel metadata.Score already uses for LowQuality.
+func Rank(r metadata.Release, prefs Prefs) int {
+	if prefs.NoCam && r.LowQuality {
+		return -1
+	}
+
+	score := metadata.Score(r)
+	if score < 0 {
+		return score
+	}
+
+	if prefs.MinResolution != "" && resolutionHeight(r.Resolution) < resolutionHeight(prefs.MinResolution) {
+		return -1
+	}
+
+	if prefs.PreferCodec != "" && strings.EqualFold(r.Codec, prefs.PreferCodec) {
+		score += codecBonus
+	}
+
+	return score
+}
+
+// Best returns the index of the highest-ranked release in releases under
+// prefs, or -1 if releases is empty or every candidate is rejected (e.g.
+// all cam rips with NoCam set).
+func Best(releases []metadata.Release, prefs Prefs) int {
+	bestIdx, bestScore := -1, -1
+	for i, r := range releases {
+		if s := Rank(r, prefs); s > bestScore {
+			bestScore = s
+			bestIdx = i
+		}
+	}
+	if bestScore < 0 {
+		return -1
+	}
+	return bestIdx
+}
+
+// resolutionHeight parses a resolution tag like "1080p" or a bare "1080"
+// into its numeric pixel height, or 0 if it doesn't look like one.
+func resolutionHeight(s string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(s), "p"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,61 @@
+package quality
+
+import (
+	"testing"
+
+	"lobster/internal/metadata"
+)
+
+func TestRankNoCam(t *testing.T) {
+	cam := metadata.Release{Resolution: "1080p", LowQuality: true}
+	if got := Rank(cam, Prefs{NoCam: true}); got != -1 {
+		t.Errorf("Rank(cam, NoCam) = %d, want -1", got)
+	}
+	if got := Rank(cam, Prefs{}); got != -1 {
+		t.Errorf("Rank(cam, {}) = %d, want -1 (metadata.Score already rejects LowQuality)", got)
+	}
+}
+
+func TestRankMinResolution(t *testing.T) {
+	r := metadata.Release{Resolution: "720p"}
+	if got := Rank(r, Prefs{MinResolution: "1080p"}); got != -1 {
+		t.Errorf("Rank(720p, MinResolution=1080p) = %d, want -1", got)
+	}
+	if got := Rank(r, Prefs{MinResolution: "480p"}); got <= 0 {
+		t.Errorf("Rank(720p, MinResolution=480p) = %d, want > 0", got)
+	}
+}
+
+func TestRankPreferCodec(t *testing.T) {
+	x265 := metadata.Release{Resolution: "1080p", Codec: "x265"}
+	x264 := metadata.Release{Resolution: "1080p", Codec: "x264"}
+
+	withoutPref := Rank(x265, Prefs{})
+	withPref := Rank(x265, Prefs{PreferCodec: "x265"})
+	if withPref <= withoutPref {
+		t.Errorf("Rank(x265, PreferCodec=x265) = %d, want > %d", withPref, withoutPref)
+	}
+	if Rank(x264, Prefs{PreferCodec: "x265"}) >= withPref {
+		t.Errorf("x264 should rank below x265 when PreferCodec=x265")
+	}
+}
+
+func TestBest(t *testing.T) {
+	releases := []metadata.Release{
+		{Resolution: "720p"},
+		{Resolution: "1080p", LowQuality: true},
+		{Resolution: "1080p"},
+	}
+	if got := Best(releases, Prefs{NoCam: true}); got != 2 {
+		t.Errorf("Best() = %d, want 2", got)
+	}
+}
+
+func TestBestAllRejected(t *testing.T) {
+	releases := []metadata.Release{
+		{Resolution: "1080p", LowQuality: true},
+	}
+	if got := Best(releases, Prefs{NoCam: true}); got != -1 {
+		t.Errorf("Best() = %d, want -1", got)
+	}
+}
@@ -0,0 +1,505 @@
+// Package server exposes a local HTTP/JSON API mirroring the Provider
+// interface, so lobster can be embedded in home-media dashboards or
+// Kodi/Jellyfin-style front-ends instead of only being driven from the
+// CLI. Every request must present the configured API token; there is no
+// anonymous mode, since a local server with no auth is a drive-by target
+// for anything else running on the same host.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lobster/internal/download"
+	"lobster/internal/extract"
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+	"lobster/internal/player"
+	"lobster/internal/provider"
+)
+
+// Config configures a Server. It's a plain struct (rather than
+// *config.Config) so this package doesn't need to import cmd's config
+// loading/validation concerns.
+type Config struct {
+	Addr        string // e.g. ":8080"
+	Token       string // required Bearer token
+	Site        string // provider registry name, e.g. "flixhq"
+	Base        string // provider host, e.g. "flixhq.to"
+	ServerName  string // preferred streaming server, e.g. "Vidcloud"
+	Quality     string // preferred stream quality
+	Player      string // media player name for /play
+	DownloadDir string // destination directory for /download
+
+	DownloadRetries        int    // per-segment/per-range transport retry count for /download
+	DownloadNamingTemplate string // output path template for /download; empty uses download.DefaultNamingTemplate
+	DownloadLayoutFormat   string // built-in layout ("movie"/"tv"/"anime") for /download when DownloadNamingTemplate is empty
+
+	// CORSOrigin, when set, is sent as Access-Control-Allow-Origin on every
+	// response (and OPTIONS preflights are answered directly), so a
+	// browser-based web UI on a different origin can call this API.
+	// Empty disables CORS handling entirely.
+	CORSOrigin string
+}
+
+// Server is the local HTTP/JSON API.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+// New creates a Server. It returns an error if cfg.Token is empty, since
+// running without a token would let anything else on the machine drive
+// playback/search through the API.
+func New(cfg Config) (*Server, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("serve requires api_token to be set in the config file")
+	}
+
+	s := &Server{cfg: cfg, mux: http.NewServeMux()}
+	s.routes()
+	return s, nil
+}
+
+// ListenAndServe starts the HTTP server. It blocks until the server
+// stops or ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              s.cfg.Addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /search", s.auth(s.handleSearch))
+	s.mux.HandleFunc("GET /trending/{type}", s.auth(s.handleTrending))
+	s.mux.HandleFunc("GET /recent/{type}", s.auth(s.handleRecent))
+	s.mux.HandleFunc("GET /detail/{id}", s.auth(s.handleDetail))
+	s.mux.HandleFunc("GET /seasons", s.auth(s.handleSeasons))
+	s.mux.HandleFunc("GET /episodes", s.auth(s.handleEpisodes))
+	s.mux.HandleFunc("GET /servers", s.auth(s.handleServers))
+	s.mux.HandleFunc("GET /stream", s.auth(s.handleStream))
+	s.mux.HandleFunc("POST /play", s.auth(s.handlePlay))
+	s.mux.HandleFunc("POST /download", s.auth(s.handleDownload))
+}
+
+// auth wraps handler, applying CORS headers (if configured) and rejecting
+// requests that don't present the configured Bearer token. Comparison is
+// constant-time to avoid a timing side-channel on the token.
+func (s *Server) auth(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.writeCORSHeaders(w)
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid token"))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// writeCORSHeaders sets the response headers a browser needs to allow a
+// cross-origin request, when s.cfg.CORSOrigin is set. A no-op otherwise.
+func (s *Server) writeCORSHeaders(w http.ResponseWriter) {
+	if s.cfg.CORSOrigin == "" {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", s.cfg.CORSOrigin)
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+}
+
+// mediaTypeFromPath maps the "{type}" path value used by /trending and
+// /recent ("movies" or "tv") to a media.MediaType, defaulting to
+// media.Movie for anything else.
+func mediaTypeFromPath(value string) media.MediaType {
+	if value == "tv" {
+		return media.TV
+	}
+	return media.Movie
+}
+
+func (s *Server) provider() (provider.Provider, error) {
+	return provider.Default.New(s.cfg.Site, s.cfg.Base)
+}
+
+// searchResponse is the JSON body returned by /search: results plus the
+// total page count, so a client knows whether to request another page.
+type searchResponse struct {
+	Results  []media.SearchResult `json:"results"`
+	LastPage int                  `json:"last_page"`
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("missing required query parameter %q", "q"))
+		return
+	}
+
+	page := 1
+	if raw := r.URL.Query().Get("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("page: must be a positive integer"))
+			return
+		}
+		page = n
+	}
+
+	p, err := s.provider()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	pager, ok := p.(provider.Pager)
+	if !ok {
+		results, err := p.Search(r.Context(), query)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, searchResponse{Results: results, LastPage: 1})
+		return
+	}
+
+	results, lastPage, err := pager.SearchPage(r.Context(), query, page)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, searchResponse{Results: results, LastPage: lastPage})
+}
+
+func (s *Server) handleTrending(w http.ResponseWriter, r *http.Request) {
+	p, err := s.provider()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	results, err := p.Trending(r.Context(), mediaTypeFromPath(r.PathValue("type")))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) handleRecent(w http.ResponseWriter, r *http.Request) {
+	p, err := s.provider()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	results, err := p.Recent(r.Context(), mediaTypeFromPath(r.PathValue("type")))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) handleDetail(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := httputil.ValidateID(id); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("id: %w", err))
+		return
+	}
+
+	p, err := s.provider()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	detail, err := p.GetDetails(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+func (s *Server) handleSeasons(w http.ResponseWriter, r *http.Request) {
+	id, err := validatedID(r, "id")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p, err := s.provider()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	seasons, err := p.GetSeasons(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, seasons)
+}
+
+func (s *Server) handleEpisodes(w http.ResponseWriter, r *http.Request) {
+	id, err := validatedID(r, "id")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	seasonID, err := validatedID(r, "season_id")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p, err := s.provider()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	episodes, err := p.GetEpisodes(r.Context(), id, seasonID)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, episodes)
+}
+
+func (s *Server) handleServers(w http.ResponseWriter, r *http.Request) {
+	id, err := validatedID(r, "id")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	episodeID := r.URL.Query().Get("episode")
+	if episodeID != "" {
+		if err := httputil.ValidateID(episodeID); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("episode: %w", err))
+			return
+		}
+	}
+
+	p, err := s.provider()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	servers, err := p.GetServers(r.Context(), id, episodeID)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, servers)
+}
+
+// streamResponse is the JSON body returned by /stream. Headers is always
+// empty today: no current extractor's resolved stream.URL needs special
+// request headers to play, unlike the embed page fetch itself (see
+// extract/megacloud.go's Referer handling). It's part of the response
+// shape so a web/mobile client that does need them for a future provider
+// doesn't require an API change to get them.
+type streamResponse struct {
+	URL       string            `json:"url"`
+	Quality   string            `json:"quality"`
+	Subtitles []media.Subtitle  `json:"subtitles"`
+	Headers   map[string]string `json:"headers"`
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	serverID, err := validatedID(r, "server_id")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	quality := r.URL.Query().Get("quality")
+	if quality == "" {
+		quality = s.cfg.Quality
+	}
+
+	stream, err := s.resolveStream(r.Context(), serverID, quality)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, streamResponse{
+		URL:       stream.URL,
+		Quality:   stream.Quality,
+		Subtitles: stream.Subtitles,
+		Headers:   map[string]string{},
+	})
+}
+
+// resolveStream gets the embed URL for serverID and decrypts it into a
+// playable stream, the same two steps resolveAndPlay performs between
+// GetServers and playback.
+func (s *Server) resolveStream(ctx context.Context, serverID, quality string) (*media.Stream, error) {
+	p, err := s.provider()
+	if err != nil {
+		return nil, err
+	}
+
+	embedURL, err := p.GetEmbedURL(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("getting embed URL: %w", err)
+	}
+
+	ext, err := extract.Default.Resolve(embedURL)
+	if err != nil {
+		return nil, fmt.Errorf("finding extractor: %w", err)
+	}
+
+	return ext.Extract(ctx, embedURL, quality)
+}
+
+// playRequest is the JSON body accepted by /play and /download. EpisodeID
+// is empty for movies. Callers get EpisodeID/ServerID from the /episodes
+// and /servers responses.
+type playRequest struct {
+	Title       string `json:"title"`
+	ServerID    string `json:"server_id"`
+	Quality     string `json:"quality,omitempty"`
+	Dir         string `json:"dir,omitempty"`         // /download only; defaults to the configured download dir
+	Concurrency int    `json:"concurrency,omitempty"` // /download only; 0 uses the single-stream ffmpeg path
+	Resume      bool   `json:"resume,omitempty"`      // /download only
+}
+
+func (s *Server) handlePlay(w http.ResponseWriter, r *http.Request) {
+	req, err := decodePlayRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	quality := req.Quality
+	if quality == "" {
+		quality = s.cfg.Quality
+	}
+
+	stream, err := s.resolveStream(r.Context(), req.ServerID, quality)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	p2 := player.New(s.cfg.Player)
+	if !p2.Available() {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("player %q not found in PATH", s.cfg.Player))
+		return
+	}
+
+	// Playback can run for hours; don't hold the HTTP request open for it.
+	go func() {
+		_, _ = p2.Play(stream, req.Title, 0, "")
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "playing", "title": req.Title})
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	req, err := decodePlayRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	quality := req.Quality
+	if quality == "" {
+		quality = s.cfg.Quality
+	}
+
+	stream, err := s.resolveStream(r.Context(), req.ServerID, quality)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	dir := req.Dir
+	if dir == "" {
+		dir = s.cfg.DownloadDir
+	}
+
+	go func() {
+		_, _ = download.Download(stream, req.Title, dir, nil, download.Options{
+			Concurrency:    req.Concurrency,
+			Resume:         req.Resume,
+			Retries:        s.cfg.DownloadRetries,
+			NamingTemplate: s.cfg.DownloadNamingTemplate,
+			LayoutFormat:   s.cfg.DownloadLayoutFormat,
+		})
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "downloading", "title": req.Title})
+}
+
+func decodePlayRequest(r *http.Request) (playRequest, error) {
+	var req playRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return playRequest{}, fmt.Errorf("decoding request body: %w", err)
+	}
+	if err := httputil.ValidateID(req.ServerID); err != nil {
+		return playRequest{}, fmt.Errorf("server_id: %w", err)
+	}
+	if req.Title == "" {
+		return playRequest{}, fmt.Errorf("missing required field %q", "title")
+	}
+	return req, nil
+}
+
+func validatedID(r *http.Request, param string) (string, error) {
+	id := r.URL.Query().Get(param)
+	if err := httputil.ValidateID(id); err != nil {
+		return "", fmt.Errorf("%s: %w", param, err)
+	}
+	return id, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,440 @@
+package subtitle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a subtitle file format lobster can render cues to.
+type Format int
+
+const (
+	FormatVTT Format = iota
+	FormatSRT
+)
+
+func (f Format) ext() string {
+	if f == FormatSRT {
+		return ".srt"
+	}
+	return ".vtt"
+}
+
+// cue is a single subtitle entry, format-agnostic.
+type cue struct {
+	start, end time.Duration
+	text       string
+}
+
+// Convert detects path's subtitle format by sniffing its contents,
+// normalizes its encoding and line endings, and renders it to target,
+// writing the result alongside the original file. It returns the path to
+// the converted file; if path is already in target format and valid
+// UTF-8, Convert still rewrites it so downstream consumers (mpv, vlc)
+// always get a clean file, never raw provider output.
+func Convert(path string, target Format) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading subtitle: %w", err)
+	}
+
+	text := toUTF8(raw)
+	text = normalizeNewlines(text)
+
+	cues, srcFormat, err := parseCues(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s subtitle: %w", srcFormat, err)
+	}
+
+	out := render(cues, target)
+
+	outPath := strings.TrimSuffix(path, filepathExt(path)) + target.ext()
+	if err := os.WriteFile(outPath, []byte(out), 0644); err != nil {
+		return "", fmt.Errorf("writing converted subtitle: %w", err)
+	}
+
+	return outPath, nil
+}
+
+func filepathExt(path string) string {
+	if idx := strings.LastIndexByte(path, '.'); idx != -1 {
+		return path[idx:]
+	}
+	return ""
+}
+
+// toUTF8 re-encodes raw into clean UTF-8 text. It strips a UTF-8 BOM if
+// present; for non-UTF-8 input it falls back to treating the bytes as
+// Windows-1252 (the common case for subtitles mojibake'd by a Latin
+// locale), since decoding true multi-byte encodings like Shift-JIS or GBK
+// would require a codec table this module doesn't otherwise depend on.
+func toUTF8(raw []byte) string {
+	raw = bytes.TrimPrefix(raw, []byte{0xEF, 0xBB, 0xBF}) // UTF-8 BOM
+
+	if bytes.HasPrefix(raw, []byte{0xFF, 0xFE}) || bytes.HasPrefix(raw, []byte{0xFE, 0xFF}) {
+		// UTF-16: decode manually rather than pulling in an encoding
+		// package for a case subtitle providers rarely hit.
+		return decodeUTF16(raw)
+	}
+
+	if isValidUTF8(raw) {
+		return string(raw)
+	}
+
+	return decodeWindows1252(raw)
+}
+
+func isValidUTF8(b []byte) bool {
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c < 0x80:
+			i++
+		case c&0xE0 == 0xC0:
+			if i+1 >= len(b) || b[i+1]&0xC0 != 0x80 {
+				return false
+			}
+			i += 2
+		case c&0xF0 == 0xE0:
+			if i+2 >= len(b) || b[i+1]&0xC0 != 0x80 || b[i+2]&0xC0 != 0x80 {
+				return false
+			}
+			i += 3
+		case c&0xF8 == 0xF0:
+			if i+3 >= len(b) || b[i+1]&0xC0 != 0x80 || b[i+2]&0xC0 != 0x80 || b[i+3]&0xC0 != 0x80 {
+				return false
+			}
+			i += 4
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// decodeWindows1252 maps each byte to its Windows-1252 codepoint. Bytes
+// 0x00-0x7F and 0xA0-0xFF match Latin-1/Unicode directly; only the
+// 0x80-0x9F range needs a lookup table.
+func decodeWindows1252(raw []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(raw))
+	for _, b := range raw {
+		if r, ok := windows1252HighBytes[b]; ok {
+			sb.WriteRune(r)
+			continue
+		}
+		sb.WriteRune(rune(b))
+	}
+	return sb.String()
+}
+
+var windows1252HighBytes = map[byte]rune{
+	0x80: '\u20AC', 0x82: '\u201A', 0x83: '\u0192', 0x84: '\u201E',
+	0x85: '\u2026', 0x86: '\u2020', 0x87: '\u2021', 0x88: '\u02C6',
+	0x89: '\u2030', 0x8A: '\u0160', 0x8B: '\u2039', 0x8C: '\u0152',
+	0x8E: '\u017D', 0x91: '\u2018', 0x92: '\u2019', 0x93: '\u201C',
+	0x94: '\u201D', 0x95: '\u2022', 0x96: '\u2013', 0x97: '\u2014',
+	0x98: '\u02DC', 0x99: '\u2122', 0x9A: '\u0161', 0x9B: '\u203A',
+	0x9C: '\u0153', 0x9E: '\u017E', 0x9F: '\u0178',
+}
+
+func decodeUTF16(raw []byte) string {
+	bigEndian := bytes.HasPrefix(raw, []byte{0xFE, 0xFF})
+	raw = raw[2:]
+
+	units := make([]uint16, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		} else {
+			units = append(units, uint16(raw[i+1])<<8|uint16(raw[i]))
+		}
+	}
+
+	runes := make([]rune, 0, len(units))
+	for i := 0; i < len(units); i++ {
+		u := units[i]
+		if u >= 0xD800 && u <= 0xDBFF && i+1 < len(units) {
+			u2 := units[i+1]
+			if u2 >= 0xDC00 && u2 <= 0xDFFF {
+				runes = append(runes, (rune(u-0xD800)<<10|rune(u2-0xDC00))+0x10000)
+				i++
+				continue
+			}
+		}
+		runes = append(runes, rune(u))
+	}
+
+	return string(runes)
+}
+
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return s
+}
+
+// parseCues sniffs text's subtitle format from its content and parses it
+// into a flat cue list.
+func parseCues(text string) ([]cue, string, error) {
+	trimmed := strings.TrimSpace(text)
+	switch {
+	case strings.HasPrefix(trimmed, "WEBVTT"):
+		return parseVTT(text), "vtt", nil
+	case strings.HasPrefix(trimmed, "<?xml") || strings.Contains(trimmed, "<tt "):
+		cues, err := parseTTML(text)
+		return cues, "ttml", err
+	case strings.Contains(text, "[Script Info]") || strings.Contains(text, "\nDialogue:") || strings.HasPrefix(trimmed, "Dialogue:"):
+		return parseASS(text), "ass", nil
+	case srtCueRe.MatchString(text):
+		return parseSRT(text), "srt", nil
+	default:
+		// Unlabeled WEBVTT (missing header) is the common "malformed VTT"
+		// case the request calls out; timestamps look identical to SRT's
+		// once commas are normalized, so fall back to the SRT parser.
+		return parseSRT(text), "srt (assumed)", nil
+	}
+}
+
+var srtCueRe = regexp.MustCompile(`\d\d:\d\d:\d\d[,.]\d+\s*-->\s*\d\d:\d\d:\d\d[,.]\d+`)
+
+// parseTimestamp parses "HH:MM:SS.mmm" or "HH:MM:SS,mmm" (and the
+// hour-omitted "MM:SS.mmm" VTT shorthand).
+func parseTimestamp(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, ",", "."))
+	parts := strings.Split(s, ":")
+
+	var h, m int
+	var secStr string
+	switch len(parts) {
+	case 3:
+		h, _ = strconv.Atoi(parts[0])
+		m, _ = strconv.Atoi(parts[1])
+		secStr = parts[2]
+	case 2:
+		m, _ = strconv.Atoi(parts[0])
+		secStr = parts[1]
+	default:
+		return 0, fmt.Errorf("malformed timestamp %q", s)
+	}
+
+	sec, err := strconv.ParseFloat(secStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed timestamp %q: %w", s, err)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec*float64(time.Second)), nil
+}
+
+// parseSRT parses SRT (and SRT-shaped malformed VTT) cues.
+func parseSRT(text string) []cue {
+	var cues []cue
+	scanner := bufio.NewScanner(strings.NewReader(text))
+
+	var lines []string
+	flush := func() {
+		if len(lines) < 2 {
+			lines = lines[:0]
+			return
+		}
+		start, end, ok := splitArrowLine(lines[0])
+		textLines := lines[1:]
+		if !ok && len(lines) >= 3 {
+			// Leading index line ("1", "2", ...); the arrow line is next.
+			start, end, ok = splitArrowLine(lines[1])
+			textLines = lines[2:]
+		}
+		if ok {
+			cues = append(cues, cue{start: start, end: end, text: stripTags(strings.Join(textLines, "\n"))})
+		}
+		lines = lines[:0]
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return cues
+}
+
+func splitArrowLine(line string) (start, end time.Duration, ok bool) {
+	idx := strings.Index(line, "-->")
+	if idx == -1 {
+		return 0, 0, false
+	}
+	startStr := strings.TrimSpace(line[:idx])
+	endStr := strings.TrimSpace(line[idx+3:])
+	if sp := strings.IndexByte(endStr, ' '); sp != -1 {
+		endStr = endStr[:sp] // drop VTT cue settings (position, align, ...)
+	}
+
+	s, err1 := parseTimestamp(startStr)
+	e, err2 := parseTimestamp(endStr)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return s, e, true
+}
+
+// parseVTT parses well-formed WebVTT cues, ignoring NOTE/STYLE blocks and
+// cue identifiers.
+func parseVTT(text string) []cue {
+	return parseSRT(text) // cue block shape is identical once "," vs "." is normalized
+}
+
+// parseASS parses Advanced SubStation Alpha Dialogue lines and strips (or
+// maps) override tags from the Text field. It assumes the standard
+// Layer,Start,End,Style,Name,MarginL,MarginR,MarginV,Effect,Text field
+// order rather than reading the [Events] Format: line, which covers every
+// ASS file produced by the usual anime-release tooling.
+func parseASS(text string) []cue {
+	var cues []cue
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "Dialogue:") {
+			continue
+		}
+
+		raw := strings.TrimPrefix(line, "Dialogue:")
+		parts := strings.SplitN(raw, ",", 10)
+		if len(parts) < 10 {
+			continue
+		}
+
+		start, err1 := parseTimestamp(strings.TrimSpace(parts[1]))
+		end, err2 := parseTimestamp(strings.TrimSpace(parts[2]))
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		cues = append(cues, cue{start: start, end: end, text: assTextToPlain(parts[9])})
+	}
+
+	return cues
+}
+
+var assOverrideRe = regexp.MustCompile(`\{[^}]*\}`)
+
+// assTextToPlain strips ASS override blocks ({\...}), mapping the common
+// italic/bold toggles to their VTT/HTML tag equivalents and converting
+// "\N"/"\n" line breaks to real newlines.
+func assTextToPlain(s string) string {
+	s = strings.ReplaceAll(s, `\N`, "\n")
+	s = strings.ReplaceAll(s, `\n`, "\n")
+
+	s = assOverrideRe.ReplaceAllStringFunc(s, func(tag string) string {
+		switch {
+		case strings.Contains(tag, `\i1`):
+			return "<i>"
+		case strings.Contains(tag, `\i0`):
+			return "</i>"
+		case strings.Contains(tag, `\b1`):
+			return "<b>"
+		case strings.Contains(tag, `\b0`):
+			return "</b>"
+		default:
+			return ""
+		}
+	})
+
+	return strings.TrimSpace(s)
+}
+
+// ttmlDoc is a minimal TTML/DFXP model covering the <p begin="" end="">
+// paragraph shape most anime sources use for closed captions.
+type ttmlDoc struct {
+	Body struct {
+		Paragraphs []struct {
+			Begin string `xml:"begin,attr"`
+			End   string `xml:"end,attr"`
+			Text  string `xml:",innerxml"`
+		} `xml:"div>p"`
+	} `xml:"body"`
+}
+
+func parseTTML(text string) ([]cue, error) {
+	var doc ttmlDoc
+	if err := xml.Unmarshal([]byte(text), &doc); err != nil {
+		return nil, fmt.Errorf("parsing TTML: %w", err)
+	}
+
+	var cues []cue
+	for _, p := range doc.Body.Paragraphs {
+		start, err1 := parseTTMLTime(p.Begin)
+		end, err2 := parseTTMLTime(p.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		plain := strings.ReplaceAll(p.Text, "<br/>", "\n")
+		plain = strings.ReplaceAll(plain, "<br />", "\n")
+		cues = append(cues, cue{start: start, end: end, text: strings.TrimSpace(stripTags(plain))})
+	}
+
+	return cues, nil
+}
+
+// parseTTMLTime parses TTML's clock-time form ("00:00:01.000"); offset-time
+// forms ("1.5s") aren't handled since no encountered provider uses them.
+func parseTTMLTime(s string) (time.Duration, error) {
+	return parseTimestamp(s)
+}
+
+var tagRe = regexp.MustCompile(`<[^>]+>`)
+
+// stripTags removes any HTML-like tags that aren't the <i>/<b>/<u> markup
+// VTT and SRT both support, leaving those intact.
+func stripTags(s string) string {
+	return tagRe.ReplaceAllStringFunc(s, func(tag string) string {
+		lower := strings.ToLower(tag)
+		for _, keep := range []string{"<i>", "</i>", "<b>", "</b>", "<u>", "</u>"} {
+			if lower == keep {
+				return tag
+			}
+		}
+		return ""
+	})
+}
+
+// render writes cues out in target's format.
+func render(cues []cue, target Format) string {
+	var sb strings.Builder
+
+	if target == FormatVTT {
+		sb.WriteString("WEBVTT\n\n")
+		for _, c := range cues {
+			fmt.Fprintf(&sb, "%s --> %s\n%s\n\n", formatTimestamp(c.start, '.'), formatTimestamp(c.end, '.'), c.text)
+		}
+		return sb.String()
+	}
+
+	for i, c := range cues {
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", i+1, formatTimestamp(c.start, ','), formatTimestamp(c.end, ','), c.text)
+	}
+	return sb.String()
+}
+
+func formatTimestamp(d time.Duration, fracSep byte) string {
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	d -= s * time.Second
+	ms := d / time.Millisecond
+
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", h, m, s, fracSep, ms)
+}
@@ -0,0 +1,62 @@
+package subtitle
+
+import "strings"
+
+// languageCodes maps a language name or its common alternate names (as
+// they appear in media.Subtitle.Language, or as a user might type them in
+// a --download-languages flag) to its ISO 639-2 code, for tagging muxed
+// subtitle tracks. Covers the languages lobster's source sites most
+// commonly surface subtitles in.
+var languageCodes = map[string]string{
+	"english":    "eng",
+	"spanish":    "spa",
+	"french":     "fre",
+	"german":     "ger",
+	"italian":    "ita",
+	"portuguese": "por",
+	"dutch":      "dut",
+	"russian":    "rus",
+	"japanese":   "jpn",
+	"korean":     "kor",
+	"chinese":    "chi",
+	"mandarin":   "chi",
+	"cantonese":  "chi",
+	"arabic":     "ara",
+	"hindi":      "hin",
+	"bengali":    "ben",
+	"turkish":    "tur",
+	"polish":     "pol",
+	"swedish":    "swe",
+	"norwegian":  "nor",
+	"danish":     "dan",
+	"finnish":    "fin",
+	"greek":      "gre",
+	"hebrew":     "heb",
+	"hungarian":  "hun",
+	"czech":      "cze",
+	"romanian":   "rum",
+	"thai":       "tha",
+	"vietnamese": "vie",
+	"indonesian": "ind",
+	"ukrainian":  "ukr",
+
+	// Already-ISO 639-2 codes pass through unchanged, so a caller that
+	// supplies "eng,spa,fre" directly (filebot-style) works the same as
+	// "English,Spanish,French".
+	"eng": "eng", "spa": "spa", "fre": "fre", "fra": "fre", "ger": "ger",
+	"deu": "ger", "ita": "ita", "por": "por", "dut": "dut", "nld": "dut",
+	"rus": "rus", "jpn": "jpn", "kor": "kor", "chi": "chi", "zho": "chi",
+	"ara": "ara", "hin": "hin", "ben": "ben", "tur": "tur", "pol": "pol",
+	"swe": "swe", "nor": "nor", "dan": "dan", "fin": "fin", "gre": "gre",
+	"ell": "gre", "heb": "heb", "hun": "hun", "cze": "cze", "ces": "cze",
+	"rum": "rum", "ron": "rum", "tha": "tha", "vie": "vie", "ind": "ind",
+	"ukr": "ukr",
+}
+
+// LanguageCode maps a language name (e.g. "English", matched
+// case-insensitively) or an already-ISO-639-2 code to its ISO 639-2 code.
+// It reports false if name isn't a recognized language.
+func LanguageCode(name string) (string, bool) {
+	code, ok := languageCodes[strings.ToLower(strings.TrimSpace(name))]
+	return code, ok
+}
@@ -0,0 +1,159 @@
+package subtitle
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+// Merge combines a dialogue track with a signs/songs track — common on
+// anime sources, where on-screen text and song lyrics ship as a separate
+// track from spoken dialogue — into a single VTT with both tracks' cues
+// layered together. Tracks are matched by language; within a language
+// group, the track whose Label mentions "sign" or "song" is treated as
+// the overlay and its cues are tagged with a <c.signs> class span so a
+// player's subtitle styling can distinguish it from dialogue. Any
+// subtitle that isn't part of a detected dialogue/signs pair is returned
+// unchanged.
+func Merge(subs []media.Subtitle) ([]media.Subtitle, error) {
+	groups := make(map[string][]media.Subtitle)
+	var order []string
+	for _, s := range subs {
+		key := strings.ToLower(s.Language)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], s)
+	}
+
+	var merged []media.Subtitle
+	for _, key := range order {
+		group := groups[key]
+
+		dialogue, signs := splitDialogueAndSigns(group)
+		if dialogue == nil || signs == nil {
+			merged = append(merged, group...)
+			continue
+		}
+
+		m, err := mergeTracks(*dialogue, *signs)
+		if err != nil {
+			// Best-effort: fall back to the unmerged tracks rather than
+			// failing the whole subtitle list over one bad pair.
+			merged = append(merged, group...)
+			continue
+		}
+		merged = append(merged, *m)
+		for i := range group {
+			if &group[i] != dialogue && &group[i] != signs {
+				merged = append(merged, group[i])
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// splitDialogueAndSigns picks the first plain track as dialogue and the
+// first "signs"/"songs"-labeled track as the overlay, if both exist.
+func splitDialogueAndSigns(subs []media.Subtitle) (dialogue, signs *media.Subtitle) {
+	for i := range subs {
+		label := strings.ToLower(subs[i].Label)
+		if strings.Contains(label, "sign") || strings.Contains(label, "song") {
+			if signs == nil {
+				signs = &subs[i]
+			}
+		} else if dialogue == nil {
+			dialogue = &subs[i]
+		}
+	}
+	return dialogue, signs
+}
+
+func mergeTracks(dialogue, signs media.Subtitle) (*media.Subtitle, error) {
+	dialogueText, err := fetchSubtitleText(dialogue.URL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching dialogue track: %w", err)
+	}
+	signsText, err := fetchSubtitleText(signs.URL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching signs/songs track: %w", err)
+	}
+
+	dialogueCues, _, err := parseCues(toUTF8([]byte(dialogueText)))
+	if err != nil {
+		return nil, fmt.Errorf("parsing dialogue track: %w", err)
+	}
+	signsCues, _, err := parseCues(toUTF8([]byte(signsText)))
+	if err != nil {
+		return nil, fmt.Errorf("parsing signs/songs track: %w", err)
+	}
+
+	for i := range signsCues {
+		signsCues[i].text = fmt.Sprintf("<c.signs>%s</c>", signsCues[i].text)
+	}
+
+	all := append(dialogueCues, signsCues...)
+	sortCues(all)
+
+	f, err := os.CreateTemp("", "lobster-merged-*.vtt")
+	if err != nil {
+		return nil, fmt.Errorf("creating merged subtitle file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(render(all, FormatVTT)); err != nil {
+		return nil, fmt.Errorf("writing merged subtitle file: %w", err)
+	}
+
+	return &media.Subtitle{
+		Language: dialogue.Language,
+		Label:    dialogue.Label + " + " + signs.Label,
+		URL:      f.Name(),
+	}, nil
+}
+
+// sortCues orders merged cues by start time so a player displays them in
+// the right sequence regardless of which track they came from.
+func sortCues(cues []cue) {
+	for i := 1; i < len(cues); i++ {
+		for j := i; j > 0 && cues[j].start < cues[j-1].start; j-- {
+			cues[j], cues[j-1] = cues[j-1], cues[j]
+		}
+	}
+}
+
+// fetchSubtitleText returns rawURL's content as a string. rawURL may also
+// be a local file path (e.g. one already downloaded by TempDir), which is
+// read directly instead of over HTTP.
+func fetchSubtitleText(rawURL string) (string, error) {
+	if httputil.ValidateURL(rawURL) != nil {
+		data, err := os.ReadFile(rawURL)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	client := httputil.NewClient(httputil.DefaultNetConfig())
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
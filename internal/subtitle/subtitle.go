@@ -55,6 +55,20 @@ func BestMatch(subtitles []media.Subtitle, language string) *media.Subtitle {
 	return &filtered[0]
 }
 
+// BestMatchMulti returns the best matching subtitle for each of languages,
+// in order, powering multi-track muxing (e.g. download.Options.Languages)
+// the same way BestMatch powers the single-language case. A language with
+// no matching subtitle is simply omitted rather than aborting the lookup.
+func BestMatchMulti(subtitles []media.Subtitle, languages []string) []media.Subtitle {
+	var matched []media.Subtitle
+	for _, lang := range languages {
+		if best := BestMatch(subtitles, lang); best != nil {
+			matched = append(matched, *best)
+		}
+	}
+	return matched
+}
+
 // TempDir manages a secure temporary directory for subtitle files.
 type TempDir struct {
 	path string
@@ -96,7 +110,7 @@ func (t *TempDir) Download(sub media.Subtitle) (string, error) {
 
 	localPath := filepath.Join(t.path, filename)
 
-	client := httputil.NewClient()
+	client := httputil.NewClient(httputil.DefaultNetConfig())
 	resp, err := client.Get(sub.URL)
 	if err != nil {
 		return "", fmt.Errorf("downloading subtitle: %w", err)
@@ -117,6 +131,16 @@ func (t *TempDir) Download(sub media.Subtitle) (string, error) {
 	if _, err := io.Copy(f, io.LimitReader(resp.Body, 10*1024*1024)); err != nil {
 		return "", fmt.Errorf("writing subtitle file: %w", err)
 	}
+	f.Close()
+
+	// Providers hand back all kinds of things under the ".vtt" label: SRT,
+	// ASS/SSA, malformed VTT missing its header, even TTML for some anime
+	// sources. Convert normalizes all of that into a clean VTT file before
+	// mpv/vlc/iina ever sees it.
+	converted, err := Convert(localPath, FormatVTT)
+	if err != nil {
+		return "", fmt.Errorf("converting subtitle: %w", err)
+	}
 
-	return localPath, nil
+	return converted, nil
 }
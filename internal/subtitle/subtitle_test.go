@@ -67,6 +67,44 @@ func TestBestMatch(t *testing.T) {
 	}
 }
 
+func TestBestMatchMulti(t *testing.T) {
+	subs := []media.Subtitle{
+		{Language: "English", Label: "English"},
+		{Language: "Spanish", Label: "Spanish"},
+		{Language: "French", Label: "French"},
+	}
+
+	got := BestMatchMulti(subs, []string{"english", "french", "japanese"})
+	if len(got) != 2 {
+		t.Fatalf("BestMatchMulti returned %d subs, want 2 (japanese has no match)", len(got))
+	}
+	if got[0].Language != "English" || got[1].Language != "French" {
+		t.Errorf("BestMatchMulti = %v, want [English French]", got)
+	}
+}
+
+func TestLanguageCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantCode string
+		wantOK   bool
+	}{
+		{"English", "eng", true},
+		{"spanish", "spa", true},
+		{"  French  ", "fre", true},
+		{"eng", "eng", true},
+		{"Klingon", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := LanguageCode(tt.name)
+		if got != tt.wantCode || ok != tt.wantOK {
+			t.Errorf("LanguageCode(%q) = (%q, %v), want (%q, %v)", tt.name, got, ok, tt.wantCode, tt.wantOK)
+		}
+	}
+}
+
 func TestTempDir(t *testing.T) {
 	tmpDir, err := NewTempDir()
 	if err != nil {
@@ -0,0 +1,275 @@
+// Package tmdb implements a minimal client for The Movie Database (TMDB)
+// API v3, used to enrich provider search results and episode listings with
+// canonical metadata: synopsis, runtime, and episode air dates. It's
+// entirely optional — every lookup is best-effort and silently skipped if
+// no API key is configured or a title can't be matched.
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"lobster/internal/cache"
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+const apiBase = "https://api.themoviedb.org/3"
+
+// enrichTTL is long: titles, overviews, and air dates essentially never
+// change once published.
+const enrichTTL = 7 * 24 * time.Hour
+
+// Client looks up movies, TV shows, and episodes on TMDB.
+type Client struct {
+	apiKey   string
+	language string
+	client   *http.Client
+	cache    *cache.Store // nil disables caching, not lookups
+}
+
+// New creates a Client. language is a TMDB locale such as "en-US"; it
+// defaults to "en-US" if empty. The cache is opened here, best-effort: if
+// it can't be created, lookups still work, just uncached.
+func New(apiKey, language string) *Client {
+	if language == "" {
+		language = "en-US"
+	}
+	store, _ := cache.New("tmdb")
+	return &Client{
+		apiKey:   apiKey,
+		language: language,
+		client:   httputil.NewClient(httputil.DefaultNetConfig()),
+		cache:    store,
+	}
+}
+
+// Enabled reports whether an API key has been configured.
+func (c *Client) Enabled() bool {
+	return c != nil && c.apiKey != ""
+}
+
+// EnrichSearchResults fills in TMDBID, Overview, and (movies only) Runtime
+// for each result by looking it up on TMDB by title and year. Lookups are
+// best-effort: a result TMDB can't match, or any request error, is left
+// untouched rather than failing the whole search.
+func (c *Client) EnrichSearchResults(ctx context.Context, results []media.SearchResult) {
+	if !c.Enabled() {
+		return
+	}
+	for i := range results {
+		c.enrichOne(ctx, &results[i])
+	}
+}
+
+func (c *Client) enrichOne(ctx context.Context, r *media.SearchResult) {
+	if r.Type == media.TV {
+		m, err := c.searchTV(ctx, r.Title, r.Year)
+		if err != nil {
+			return
+		}
+		r.TMDBID = m.ID
+		r.Overview = m.Overview
+		return
+	}
+
+	m, err := c.searchMovie(ctx, r.Title, r.Year)
+	if err != nil {
+		return
+	}
+	r.TMDBID = m.ID
+	r.Overview = m.Overview
+	r.Runtime = m.Runtime
+}
+
+// EnrichEpisodes fills Title and AirDate on each episode by fetching tvID's
+// season from TMDB and matching by episode number. It's a no-op if tvID is
+// 0 (the show wasn't matched by EnrichSearchResults) or no API key is
+// configured. Results are cached on disk keyed by tvID, so revisiting a
+// season doesn't re-hit the API.
+func (c *Client) EnrichEpisodes(ctx context.Context, tvID int, season int, episodes []media.Episode) {
+	if !c.Enabled() || tvID == 0 {
+		return
+	}
+
+	eps, err := c.seasonEpisodes(ctx, tvID, season)
+	if err != nil {
+		return
+	}
+
+	byNumber := make(map[int]episodeDetail, len(eps))
+	for _, e := range eps {
+		byNumber[e.EpisodeNumber] = e
+	}
+
+	for i := range episodes {
+		e, ok := byNumber[episodes[i].Number]
+		if !ok {
+			continue
+		}
+		if e.Name != "" {
+			episodes[i].Title = e.Name
+		}
+		episodes[i].AirDate = e.AirDate
+	}
+}
+
+type movieMatch struct {
+	ID       int    `json:"id"`
+	Overview string `json:"overview"`
+	Runtime  int    `json:"runtime"`
+}
+
+func (c *Client) searchMovie(ctx context.Context, title, year string) (movieMatch, error) {
+	key := "movie-search:" + strings.ToLower(title) + ":" + year
+	var cached movieMatch
+	if c.getCached(key, &cached) {
+		return cached, nil
+	}
+
+	params := url.Values{"query": {title}}
+	if year != "" {
+		params.Set("year", year)
+	}
+	body, err := c.get(ctx, "/search/movie", params)
+	if err != nil {
+		return movieMatch{}, err
+	}
+
+	var resp struct {
+		Results []struct {
+			ID       int    `json:"id"`
+			Overview string `json:"overview"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return movieMatch{}, fmt.Errorf("parsing TMDB movie search response: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return movieMatch{}, fmt.Errorf("no TMDB match for %q", title)
+	}
+
+	m := movieMatch{ID: resp.Results[0].ID, Overview: resp.Results[0].Overview}
+
+	// Runtime lives on the movie detail endpoint, not the search result.
+	if detail, err := c.get(ctx, fmt.Sprintf("/movie/%d", m.ID), nil); err == nil {
+		var d struct {
+			Runtime int `json:"runtime"`
+		}
+		if json.Unmarshal(detail, &d) == nil {
+			m.Runtime = d.Runtime
+		}
+	}
+
+	c.setCached(key, m)
+	return m, nil
+}
+
+type tvMatch struct {
+	ID       int    `json:"id"`
+	Overview string `json:"overview"`
+}
+
+func (c *Client) searchTV(ctx context.Context, title, year string) (tvMatch, error) {
+	key := "tv-search:" + strings.ToLower(title) + ":" + year
+	var cached tvMatch
+	if c.getCached(key, &cached) {
+		return cached, nil
+	}
+
+	params := url.Values{"query": {title}}
+	if year != "" {
+		params.Set("first_air_date_year", year)
+	}
+	body, err := c.get(ctx, "/search/tv", params)
+	if err != nil {
+		return tvMatch{}, err
+	}
+
+	var resp struct {
+		Results []struct {
+			ID       int    `json:"id"`
+			Overview string `json:"overview"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return tvMatch{}, fmt.Errorf("parsing TMDB tv search response: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return tvMatch{}, fmt.Errorf("no TMDB match for %q", title)
+	}
+
+	m := tvMatch{ID: resp.Results[0].ID, Overview: resp.Results[0].Overview}
+	c.setCached(key, m)
+	return m, nil
+}
+
+type episodeDetail struct {
+	EpisodeNumber int    `json:"episode_number"`
+	Name          string `json:"name"`
+	AirDate       string `json:"air_date"`
+}
+
+func (c *Client) seasonEpisodes(ctx context.Context, tvID, season int) ([]episodeDetail, error) {
+	key := fmt.Sprintf("tv-season:%d:%d", tvID, season)
+	var cached []episodeDetail
+	if c.getCached(key, &cached) {
+		return cached, nil
+	}
+
+	body, err := c.get(ctx, fmt.Sprintf("/tv/%d/season/%d", tvID, season), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Episodes []episodeDetail `json:"episodes"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing TMDB season response: %w", err)
+	}
+
+	c.setCached(key, resp.Episodes)
+	return resp.Episodes, nil
+}
+
+// get issues an authenticated GET against path on the TMDB API.
+func (c *Client) get(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("api_key", c.apiKey)
+	params.Set("language", c.language)
+
+	return httputil.GetJSONCtx(ctx, c.client, apiBase+path+"?"+params.Encode())
+}
+
+// getCached unmarshals a fresh cache entry for key into v, reporting
+// whether one was found.
+func (c *Client) getCached(key string, v interface{}) bool {
+	if c.cache == nil {
+		return false
+	}
+	entry, ok := c.cache.Get(key)
+	if !ok || !entry.Fresh {
+		return false
+	}
+	return json.Unmarshal(entry.Data, v) == nil
+}
+
+// setCached stores v for key, best-effort.
+func (c *Client) setCached(key string, v interface{}) {
+	if c.cache == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_ = c.cache.Set(key, data, enrichTTL, "")
+}
@@ -0,0 +1,159 @@
+// Package torrent implements a client for Torznab-compatible indexers, used
+// by the optional torrent playback source.
+package torrent
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lobster/internal/httputil"
+	"lobster/internal/metadata"
+)
+
+// Result is a single torrent candidate returned by Search.
+type Result struct {
+	Title     string
+	MagnetURI string
+	Seeders   int
+	SizeBytes int64
+	Release   metadata.Release
+}
+
+// SearchParams configures Client.Search.
+type SearchParams struct {
+	Query   string
+	Season  int // 0 for movies
+	Episode int // 0 for movies
+
+	CheckFileSize   bool // reject entries with no reported size
+	CheckResolution bool // reject entries with no recognized resolution
+	MinSeeders      int
+}
+
+// Client searches a single Torznab-compatible indexer.
+type Client struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// New creates a Client for the Torznab API rooted at baseURL (e.g.
+// "https://indexer.example/api"), authenticating with apiKey.
+func New(baseURL, apiKey string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  httputil.NewClient(httputil.DefaultNetConfig()),
+	}
+}
+
+// torznabFeed mirrors the subset of a Torznab RSS response we need.
+type torznabFeed struct {
+	Items []torznabItem `xml:"channel>item"`
+}
+
+type torznabItem struct {
+	Title string        `xml:"title"`
+	Link  string        `xml:"link"`
+	Attrs []torznabAttr `xml:"attr"`
+}
+
+type torznabAttr struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func (i torznabItem) attr(name string) string {
+	for _, a := range i.Attrs {
+		if strings.EqualFold(a.Name, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Search queries the indexer for params.Query (plus season/episode for
+// TV), drops low-quality (cam/telesync) releases unconditionally, applies
+// params' CheckFileSize/CheckResolution/MinSeeders filters, and returns the
+// survivors sorted best quality first.
+func (c *Client) Search(ctx context.Context, params SearchParams) ([]Result, error) {
+	query := params.Query
+	if params.Season > 0 && params.Episode > 0 {
+		query = fmt.Sprintf("%s S%02dE%02d", query, params.Season, params.Episode)
+	}
+
+	apiURL := fmt.Sprintf("%s?t=search&q=%s&apikey=%s",
+		c.baseURL, url.QueryEscape(query), url.QueryEscape(c.apiKey))
+
+	resp, err := httputil.GetCtx(ctx, c.client, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("querying indexer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from indexer", resp.StatusCode)
+	}
+
+	var feed torznabFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("parsing Torznab response: %w", err)
+	}
+
+	var results []Result
+	for _, item := range feed.Items {
+		release := metadata.Parse(item.Title)
+		if release.LowQuality {
+			continue
+		}
+
+		magnet := item.attr("magneturl")
+		if magnet == "" {
+			magnet = item.Link
+		}
+		if !strings.HasPrefix(magnet, "magnet:") {
+			continue
+		}
+
+		seeders, _ := strconv.Atoi(item.attr("seeders"))
+		if seeders < params.MinSeeders {
+			continue
+		}
+
+		size, _ := strconv.ParseInt(item.attr("size"), 10, 64)
+		if params.CheckFileSize && size == 0 {
+			continue
+		}
+		if params.CheckResolution && release.Resolution == "" {
+			continue
+		}
+
+		results = append(results, Result{
+			Title:     item.Title,
+			MagnetURI: magnet,
+			Seeders:   seeders,
+			SizeBytes: size,
+			Release:   release,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		si, sj := metadata.Score(results[i].Release), metadata.Score(results[j].Release)
+		if si != sj {
+			return si > sj
+		}
+		return results[i].Seeders > results[j].Seeders
+	})
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results found for %q", params.Query)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,334 @@
+// Package trakt implements an optional Trakt.tv scrobble/sync backend for
+// the history subsystem. Authentication uses the OAuth device-code flow
+// (no client secret needed in a terminal app), and tokens are persisted
+// under XDG_DATA_HOME/lobster/ so a single `lobster trakt auth` survives
+// across runs.
+package trakt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"lobster/internal/httputil"
+	"lobster/internal/media"
+)
+
+const (
+	apiBase  = "https://api.trakt.tv"
+	clientID = "e5d12c1d41f7dbf6876d07b23d9a7e2a03f0a5a7fa4ab7f4c1b3c86c5b4f9a21"
+)
+
+// Tokens holds the OAuth access/refresh tokens issued by Trakt.
+type Tokens struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Client talks to the Trakt.tv API for scrobbling and history sync.
+type Client struct {
+	http   *http.Client
+	tokens *Tokens
+}
+
+// New creates a Trakt client, loading any previously saved tokens.
+func New() (*Client, error) {
+	c := &Client{http: httputil.NewClient(httputil.DefaultNetConfig())}
+	tokens, err := loadTokens()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("loading trakt tokens: %w", err)
+	}
+	c.tokens = tokens
+	return c, nil
+}
+
+// Authenticated reports whether we have a stored access token.
+func (c *Client) Authenticated() bool {
+	return c.tokens != nil && c.tokens.AccessToken != ""
+}
+
+// deviceCodeResponse is returned by POST /oauth/device/code.
+type deviceCodeResponse struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// tokenResponse is returned by POST /oauth/device/token.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// Authenticate runs the device-code flow, printing the user code and
+// verification URL, then polling until the user approves or it expires.
+func (c *Client) Authenticate() error {
+	body, err := c.postJSON("/oauth/device/code", map[string]string{"client_id": clientID}, "")
+	if err != nil {
+		return fmt.Errorf("requesting device code: %w", err)
+	}
+
+	var dc deviceCodeResponse
+	if err := json.Unmarshal(body, &dc); err != nil {
+		return fmt.Errorf("parsing device code response: %w", err)
+	}
+
+	fmt.Printf("Go to %s and enter code: %s\n", dc.VerificationURL, dc.UserCode)
+
+	interval := time.Duration(dc.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(dc.ExpiresIn) * time.Second)
+
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		body, err := c.postJSON("/oauth/device/token", map[string]string{
+			"code":      dc.DeviceCode,
+			"client_id": clientID,
+		}, "")
+		if err != nil {
+			continue // Not yet authorized (400/404 while pending)
+		}
+
+		var tok tokenResponse
+		if err := json.Unmarshal(body, &tok); err != nil {
+			return fmt.Errorf("parsing token response: %w", err)
+		}
+
+		c.tokens = &Tokens{
+			AccessToken:  tok.AccessToken,
+			RefreshToken: tok.RefreshToken,
+			ExpiresAt:    time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second),
+		}
+		return saveTokens(c.tokens)
+	}
+
+	return fmt.Errorf("device authorization expired before approval")
+}
+
+// Pull fetches watched-in-progress history from Trakt so it can be merged
+// into the local TSV. It implements history.RemoteSync.
+func (c *Client) Pull() ([]media.HistoryEntry, error) {
+	if !c.Authenticated() {
+		return nil, fmt.Errorf("not authenticated with trakt")
+	}
+
+	body, err := c.get("/sync/playback", c.tokens.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("fetching playback progress: %w", err)
+	}
+
+	var items []struct {
+		Progress float64 `json:"progress"`
+		Type     string  `json:"type"`
+		Movie    *struct {
+			IDs struct{ Slug string } `json:"ids"`
+		} `json:"movie"`
+		Episode *struct {
+			Season int                   `json:"season"`
+			Number int                    `json:"number"`
+			IDs    struct{ Slug string } `json:"ids"`
+		} `json:"episode"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("parsing playback progress: %w", err)
+	}
+
+	var entries []media.HistoryEntry
+	for _, it := range items {
+		switch {
+		case it.Movie != nil:
+			entries = append(entries, media.HistoryEntry{
+				ID:       it.Movie.IDs.Slug,
+				Type:     media.Movie,
+				Position: it.Progress,
+			})
+		case it.Episode != nil:
+			entries = append(entries, media.HistoryEntry{
+				ID:      it.Episode.IDs.Slug,
+				Type:    media.TV,
+				Season:  it.Episode.Season,
+				Episode: it.Episode.Number,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// Push mirrors a saved local history entry to Trakt as a scrobble stop,
+// implementing history.RemoteSync.
+func (c *Client) Push(entry media.HistoryEntry) error {
+	progress := 0.0
+	if entry.Duration > 0 {
+		progress = entry.Position / entry.Duration * 100
+	}
+	return c.Stop(entry, progress)
+}
+
+func (c *Client) get(path, accessToken string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, apiBase+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("trakt-api-version", "2")
+	req.Header.Set("trakt-api-key", clientID)
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, path)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+}
+
+// Scrobbler maps mpv IPC playback events to Trakt scrobble calls.
+type Scrobbler interface {
+	Start(entry media.HistoryEntry, progress float64) error
+	Pause(entry media.HistoryEntry, progress float64) error
+	Stop(entry media.HistoryEntry, progress float64) error
+}
+
+// Start reports that playback has begun, at progress percent (0-100).
+func (c *Client) Start(entry media.HistoryEntry, progress float64) error {
+	return c.scrobble("start", entry, progress)
+}
+
+// Pause reports that playback has paused.
+func (c *Client) Pause(entry media.HistoryEntry, progress float64) error {
+	return c.scrobble("pause", entry, progress)
+}
+
+// Stop reports that playback has stopped or finished.
+func (c *Client) Stop(entry media.HistoryEntry, progress float64) error {
+	return c.scrobble("stop", entry, progress)
+}
+
+func (c *Client) scrobble(action string, entry media.HistoryEntry, progress float64) error {
+	if !c.Authenticated() {
+		return fmt.Errorf("not authenticated with trakt")
+	}
+
+	ids, err := c.resolveIDs(entry)
+	if err != nil {
+		return fmt.Errorf("resolving trakt ids: %w", err)
+	}
+
+	payload := map[string]interface{}{"progress": progress}
+	if entry.Type == media.TV {
+		payload["episode"] = map[string]interface{}{"ids": ids}
+	} else {
+		payload["movie"] = map[string]interface{}{"ids": ids}
+	}
+
+	_, err = c.postJSON("/scrobble/"+action, payload, c.tokens.AccessToken)
+	return err
+}
+
+// resolveIDs returns the Trakt IDs for a history entry. It does not query
+// Trakt's search endpoint: the entry's provider ID is passed through as
+// the slug.
+func (c *Client) resolveIDs(entry media.HistoryEntry) (map[string]string, error) {
+	// A full implementation would query /search/movie or /search/show and
+	// match by title/year; for now we key directly on the provider ID,
+	// which Trakt treats as an opaque "slug" candidate.
+	return map[string]string{"slug": entry.ID}, nil
+}
+
+// postJSON POSTs a JSON payload with the standard Trakt headers, optionally
+// bearer-authenticated, and returns the raw response body.
+func (c *Client) postJSON(path string, payload interface{}, accessToken string) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, apiBase+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("trakt-api-version", "2")
+	req.Header.Set("trakt-api-key", clientID)
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, path)
+	}
+
+	return body, nil
+}
+
+func tokensPath() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("getting home directory: %w", err)
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "lobster", "trakt-tokens.json"), nil
+}
+
+func loadTokens() (*Tokens, error) {
+	path, err := tokensPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tok Tokens
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, fmt.Errorf("parsing stored trakt tokens: %w", err)
+	}
+	return &tok, nil
+}
+
+func saveTokens(tok *Tokens) error {
+	path, err := tokensPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating trakt data dir: %w", err)
+	}
+	data, err := json.Marshal(tok)
+	if err != nil {
+		return fmt.Errorf("encoding trakt tokens: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
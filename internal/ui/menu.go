@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// menuPrograms are probed in order when no specific binary is requested.
+var menuPrograms = []string{"rofi", "dmenu", "wofi"}
+
+// MenuSelector drives selection through a dmenu-compatible launcher
+// (rofi/dmenu/wofi). Items are passed as plain text via stdin, exactly
+// like FzfSelector — no shell interpretation of item text.
+type MenuSelector struct {
+	bin string // resolved absolute path to rofi/dmenu/wofi
+}
+
+// newMenuSelector resolves want ("rofi", "dmenu", "wofi", or "" to probe
+// menuPrograms in order) to an absolute binary path, returning an error if
+// none is found on PATH.
+func newMenuSelector(want string) (MenuSelector, error) {
+	candidates := menuPrograms
+	if want != "" {
+		candidates = []string{want}
+	}
+
+	for _, name := range candidates {
+		if path, err := exec.LookPath(name); err == nil {
+			return MenuSelector{bin: path}, nil
+		}
+	}
+	return MenuSelector{}, fmt.Errorf("no menu program found in PATH (tried: %s)", strings.Join(candidates, ", "))
+}
+
+// args returns the dmenu-protocol flags for prompt. rofi and wofi both
+// accept dmenu's -p/-dmenu convention; bare dmenu just wants -p.
+func (m MenuSelector) args(prompt string) []string {
+	if strings.Contains(m.bin, "dmenu") {
+		return []string{"-p", prompt}
+	}
+	return []string{"-dmenu", "-p", prompt}
+}
+
+// Select presents items (numbered, like FzfSelector) through the menu
+// program and returns the chosen index.
+func (m MenuSelector) Select(prompt string, items []string) (int, error) {
+	if len(items) == 0 {
+		return -1, fmt.Errorf("no items to select from")
+	}
+
+	var input strings.Builder
+	for i, item := range items {
+		fmt.Fprintf(&input, "%d\t%s\n", i, item)
+	}
+
+	cmd := exec.Command(m.bin, m.args(prompt)...)
+	cmd.Stdin = strings.NewReader(input.String())
+	cmd.Stderr = os.Stderr
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return -1, fmt.Errorf("%s failed: %w", filepathBase(m.bin), err)
+	}
+
+	selected := strings.TrimSpace(stdout.String())
+	if selected == "" {
+		return -1, fmt.Errorf("no selection made")
+	}
+
+	parts := strings.SplitN(selected, "\t", 2)
+	idx, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return -1, fmt.Errorf("parsing selection index: %w", err)
+	}
+	if idx < 0 || idx >= len(items) {
+		return -1, fmt.Errorf("selection index %d out of range", idx)
+	}
+
+	return idx, nil
+}
+
+// Confirm asks the user a yes/no question via the menu program.
+func (m MenuSelector) Confirm(prompt string) (bool, error) {
+	idx, err := m.Select(prompt, []string{"Yes", "No"})
+	if err != nil {
+		return false, err
+	}
+	return idx == 0, nil
+}
+
+// Input prompts for free-text input via the menu program with no
+// candidate list, so whatever the user types is returned verbatim.
+func (m MenuSelector) Input(prompt string) (string, error) {
+	cmd := exec.Command(m.bin, m.args(prompt)...)
+	cmd.Stdin = strings.NewReader("")
+	cmd.Stderr = os.Stderr
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %w", filepathBase(m.bin), err)
+	}
+
+	query := strings.TrimSpace(stdout.String())
+	if query == "" {
+		return "", fmt.Errorf("no input provided")
+	}
+
+	return query, nil
+}
+
+// filepathBase returns the last path component without pulling in
+// path/filepath just for error messages.
+func filepathBase(p string) string {
+	idx := strings.LastIndexByte(p, '/')
+	if idx == -1 {
+		return p
+	}
+	return p[idx+1:]
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PlainSelector is a headless, fzf-free fallback: it prints a numbered
+// list to stdout and reads the choice back from stdin via bufio.Scanner.
+// Set LOBSTER_CHOICE to the desired index to drive it non-interactively
+// (e.g. from a script or CI), skipping the stdin read entirely.
+type PlainSelector struct{}
+
+// Select prints items as a numbered list and reads the chosen index from
+// stdin, or from $LOBSTER_CHOICE if set.
+func (PlainSelector) Select(prompt string, items []string) (int, error) {
+	if len(items) == 0 {
+		return -1, fmt.Errorf("no items to select from")
+	}
+
+	if choice := os.Getenv("LOBSTER_CHOICE"); choice != "" {
+		idx, err := strconv.Atoi(strings.TrimSpace(choice))
+		if err != nil {
+			return -1, fmt.Errorf("parsing LOBSTER_CHOICE: %w", err)
+		}
+		if idx < 0 || idx >= len(items) {
+			return -1, fmt.Errorf("LOBSTER_CHOICE %d out of range", idx)
+		}
+		return idx, nil
+	}
+
+	fmt.Printf("%s:\n", prompt)
+	for i, item := range items {
+		fmt.Printf("  %d) %s\n", i, item)
+	}
+	fmt.Print("> ")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return -1, fmt.Errorf("no selection made")
+	}
+
+	idx, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		return -1, fmt.Errorf("parsing selection: %w", err)
+	}
+	if idx < 0 || idx >= len(items) {
+		return -1, fmt.Errorf("selection index %d out of range", idx)
+	}
+
+	return idx, nil
+}
+
+// Confirm asks a yes/no question, reading a y/n line from stdin.
+func (PlainSelector) Confirm(prompt string) (bool, error) {
+	if choice := os.Getenv("LOBSTER_CHOICE"); choice != "" {
+		switch strings.ToLower(strings.TrimSpace(choice)) {
+		case "0", "y", "yes":
+			return true, nil
+		case "1", "n", "no":
+			return false, nil
+		default:
+			return false, fmt.Errorf("parsing LOBSTER_CHOICE %q as yes/no", choice)
+		}
+	}
+
+	fmt.Printf("%s [y/N] ", prompt)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return false, fmt.Errorf("no input provided")
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes", nil
+}
+
+// Input prompts for and reads a line of free-text input from stdin.
+func (PlainSelector) Input(prompt string) (string, error) {
+	if choice := os.Getenv("LOBSTER_CHOICE"); choice != "" {
+		return choice, nil
+	}
+
+	fmt.Printf("%s > ", prompt)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return "", fmt.Errorf("no input provided")
+	}
+
+	query := strings.TrimSpace(scanner.Text())
+	if query == "" {
+		return "", fmt.Errorf("no input provided")
+	}
+
+	return query, nil
+}
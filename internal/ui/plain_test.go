@@ -0,0 +1,53 @@
+package ui
+
+import "testing"
+
+func TestPlainSelectorSelectUsesLobsterChoice(t *testing.T) {
+	t.Setenv("LOBSTER_CHOICE", "1")
+
+	idx, err := (PlainSelector{}).Select("pick one", []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Select() error: %v", err)
+	}
+	if idx != 1 {
+		t.Errorf("Select() = %d, want 1", idx)
+	}
+}
+
+func TestPlainSelectorSelectRejectsOutOfRangeChoice(t *testing.T) {
+	t.Setenv("LOBSTER_CHOICE", "5")
+
+	if _, err := (PlainSelector{}).Select("pick one", []string{"a", "b"}); err == nil {
+		t.Error("Select() with out-of-range LOBSTER_CHOICE should error")
+	}
+}
+
+func TestPlainSelectorConfirmUsesLobsterChoice(t *testing.T) {
+	t.Setenv("LOBSTER_CHOICE", "yes")
+
+	ok, err := (PlainSelector{}).Confirm("really?")
+	if err != nil {
+		t.Fatalf("Confirm() error: %v", err)
+	}
+	if !ok {
+		t.Error("Confirm() = false, want true for LOBSTER_CHOICE=yes")
+	}
+}
+
+func TestPlainSelectorInputUsesLobsterChoice(t *testing.T) {
+	t.Setenv("LOBSTER_CHOICE", "the exorcist")
+
+	got, err := (PlainSelector{}).Input("Search")
+	if err != nil {
+		t.Fatalf("Input() error: %v", err)
+	}
+	if got != "the exorcist" {
+		t.Errorf("Input() = %q, want %q", got, "the exorcist")
+	}
+}
+
+func TestPlainSelectorSelectRejectsEmptyItems(t *testing.T) {
+	if _, err := (PlainSelector{}).Select("pick one", nil); err == nil {
+		t.Error("Select() with no items should error")
+	}
+}
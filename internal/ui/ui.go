@@ -1,119 +1,82 @@
-// Package ui provides a secure fzf launcher abstraction.
-// All items are piped to fzf via stdin as plain text — no shell-interpreted
-// preview strings or commands with remote data.
-package ui
-
-import (
-	"bytes"
-	"fmt"
-	"os"
-	"os/exec"
-	"strings"
-)
-
-// Select presents items to the user via fzf and returns the selected item's index.
-// Items are passed as plain text via stdin. No --preview or shell-evaluated strings.
-func Select(prompt string, items []string) (int, error) {
-	if len(items) == 0 {
-		return -1, fmt.Errorf("no items to select from")
-	}
-
-	// Check if fzf is available
-	fzfPath, err := exec.LookPath("fzf")
-	if err != nil {
-		return -1, fmt.Errorf("fzf not found in PATH: %w", err)
-	}
-
-	// Prepare numbered items for reliable index extraction
-	var input strings.Builder
-	for i, item := range items {
-		fmt.Fprintf(&input, "%d\t%s\n", i, item)
-	}
-
-	// Build fzf command with safe arguments only
-	cmd := exec.Command(fzfPath,
-		"--prompt", prompt+" > ",
-		"--height", "40%",
-		"--reverse",
-		"--with-nth", "2..", // Display from second field onward (hide index)
-		"--delimiter", "\t",
-		"--no-multi",
-		"--cycle",
-	)
-
-	cmd.Stdin = strings.NewReader(input.String())
-	cmd.Stderr = os.Stderr
-
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 130 {
-			return -1, fmt.Errorf("selection cancelled")
-		}
-		return -1, fmt.Errorf("fzf failed: %w", err)
-	}
-
-	selected := strings.TrimSpace(stdout.String())
-	if selected == "" {
-		return -1, fmt.Errorf("no selection made")
-	}
-
-	// Extract the index from the first tab-separated field
-	parts := strings.SplitN(selected, "\t", 2)
-	if len(parts) == 0 {
-		return -1, fmt.Errorf("unexpected fzf output format")
-	}
-
-	var idx int
-	if _, err := fmt.Sscanf(parts[0], "%d", &idx); err != nil {
-		return -1, fmt.Errorf("parsing selection index: %w", err)
-	}
-
-	if idx < 0 || idx >= len(items) {
-		return -1, fmt.Errorf("selection index %d out of range", idx)
-	}
-
-	return idx, nil
-}
-
-// Confirm asks the user a yes/no question via fzf.
-func Confirm(prompt string) (bool, error) {
-	idx, err := Select(prompt, []string{"Yes", "No"})
-	if err != nil {
-		return false, err
-	}
-	return idx == 0, nil
-}
-
-// Input prompts the user for free-text input via fzf's --print-query.
-func Input(prompt string) (string, error) {
-	fzfPath, err := exec.LookPath("fzf")
-	if err != nil {
-		return "", fmt.Errorf("fzf not found in PATH: %w", err)
-	}
-
-	cmd := exec.Command(fzfPath,
-		"--prompt", prompt+" > ",
-		"--height", "10%",
-		"--reverse",
-		"--print-query",
-		"--no-info",
-	)
-
-	cmd.Stdin = strings.NewReader("")
-	cmd.Stderr = os.Stderr
-
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	// fzf exits 1 when using --print-query with no match, which is expected
-	_ = cmd.Run()
-
-	query := strings.TrimSpace(strings.Split(stdout.String(), "\n")[0])
-	if query == "" {
-		return "", fmt.Errorf("no input provided")
-	}
-
-	return query, nil
-}
+// Package ui provides a secure, pluggable user-selection abstraction.
+// Every backend passes items to the underlying program via stdin as plain
+// text — no shell-interpreted preview strings or commands built from
+// remote data.
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Selector presents choices to the user and reads back free text.
+type Selector interface {
+	// Select presents items under prompt and returns the chosen index.
+	Select(prompt string, items []string) (int, error)
+
+	// Confirm asks a yes/no question.
+	Confirm(prompt string) (bool, error)
+
+	// Input prompts for free-text input.
+	Input(prompt string) (string, error)
+}
+
+// New returns the Selector named by backend: "fzf", "plain", "menu" (probe
+// rofi/dmenu/wofi in that order), or an explicit "rofi"/"dmenu"/"wofi". It
+// errors if backend isn't recognized or its binary isn't on PATH.
+func New(backend string) (Selector, error) {
+	switch backend {
+	case "fzf":
+		if _, err := exec.LookPath("fzf"); err != nil {
+			return nil, fmt.Errorf("ui backend %q requested but fzf not found in PATH", backend)
+		}
+		return FzfSelector{}, nil
+	case "plain":
+		return PlainSelector{}, nil
+	case "menu":
+		return newMenuSelector("")
+	case "rofi", "dmenu", "wofi":
+		return newMenuSelector(backend)
+	default:
+		return nil, fmt.Errorf("unsupported ui backend %q (valid: fzf, plain, menu, rofi, dmenu, wofi)", backend)
+	}
+}
+
+// Default auto-detects a Selector: $LOBSTER_UI overrides everything,
+// otherwise plain stdin/stdout if stdin isn't a terminal, otherwise fzf if
+// it's on PATH, otherwise a menu program if a display is available,
+// otherwise plain as the last resort.
+func Default() Selector {
+	if backend := os.Getenv("LOBSTER_UI"); backend != "" {
+		if s, err := New(backend); err == nil {
+			return s
+		}
+	}
+
+	if !isTerminal(os.Stdin) {
+		return PlainSelector{}
+	}
+
+	if _, err := exec.LookPath("fzf"); err == nil {
+		return FzfSelector{}
+	}
+
+	if os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("DISPLAY") != "" {
+		if s, err := newMenuSelector(""); err == nil {
+			return s
+		}
+	}
+
+	return PlainSelector{}
+}
+
+// isTerminal reports whether f looks like an interactive terminal rather
+// than a pipe or redirected file.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,19 @@
+package ui
+
+import "testing"
+
+func TestNewPlain(t *testing.T) {
+	s, err := New("plain")
+	if err != nil {
+		t.Fatalf("New(\"plain\") error: %v", err)
+	}
+	if _, ok := s.(PlainSelector); !ok {
+		t.Errorf("New(\"plain\") = %T, want PlainSelector", s)
+	}
+}
+
+func TestNewUnsupportedBackend(t *testing.T) {
+	if _, err := New("carrier-pigeon"); err == nil {
+		t.Error("New() with an unsupported backend should error")
+	}
+}
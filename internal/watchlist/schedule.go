@@ -0,0 +1,26 @@
+package watchlist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseInterval parses a --daemon schedule spec into a run interval.
+// Supported forms are a bare Go duration ("6h", "30m") or the "@every
+// <duration>" convention several cron libraries use for simple interval
+// schedules; full crontab field syntax isn't supported, since a plain
+// interval is all a single-user watchlist daemon needs.
+func ParseInterval(spec string) (time.Duration, error) {
+	s := strings.TrimSpace(spec)
+	s = strings.TrimPrefix(s, "@every ")
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("parsing interval %q: %w", spec, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("interval must be positive, got %q", spec)
+	}
+	return d, nil
+}
@@ -0,0 +1,226 @@
+// Package watchlist tracks TV shows the user wants to auto-follow, diffing
+// each sync's GetSeasons/GetEpisodes results against the last season/episode
+// seen per entry. State is stored as JSON (unlike history's TSV) because
+// entries carry several optional, occasionally-nested fields and there's no
+// append-only access pattern to optimize for.
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"lobster/internal/config"
+	"lobster/internal/media"
+	"lobster/internal/provider"
+)
+
+// Entry is a single watchlist subscription.
+type Entry struct {
+	Provider        string          `json:"provider"`   // registry name, e.g. "flixhq"
+	ContentID       string          `json:"content_id"` // provider-specific show ID
+	Title           string          `json:"title"`
+	Type            media.MediaType `json:"type"`
+	LastSeenSeason  int             `json:"last_seen_season"`
+	LastSeenEpisode int             `json:"last_seen_episode"`
+	Filter          string          `json:"filter,omitempty"`        // only match episode titles containing this substring
+	MaxAgeDays      int             `json:"max_age_days,omitempty"`  // 0: unlimited; requires TMDB-enriched air dates
+}
+
+// NewEpisode is a single new episode surfaced by Sync.
+type NewEpisode struct {
+	Entry   Entry
+	Season  int
+	Episode media.Episode
+}
+
+type state struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads all watchlist entries. A missing state file is not an error;
+// it returns an empty list.
+func Load() ([]Entry, error) {
+	path, err := config.WatchlistPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading watchlist: %w", err)
+	}
+
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing watchlist: %w", err)
+	}
+	return s.Entries, nil
+}
+
+// Save overwrites the watchlist state file with entries, atomically.
+func Save(entries []Entry) error {
+	path, err := config.WatchlistPath()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating watchlist dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state{Entries: entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding watchlist: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "watchlist-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing watchlist: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming watchlist file: %w", err)
+	}
+	return nil
+}
+
+// Add appends entry to the watchlist, replacing any existing entry with the
+// same Provider/ContentID.
+func Add(entry Entry) error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+
+	for i, e := range entries {
+		if e.Provider == entry.Provider && e.ContentID == entry.ContentID {
+			entries[i] = entry
+			return Save(entries)
+		}
+	}
+
+	entries = append(entries, entry)
+	return Save(entries)
+}
+
+// Remove deletes the entry matching providerName/contentID, if present.
+func Remove(providerName, contentID string) error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+
+	var filtered []Entry
+	for _, e := range entries {
+		if e.Provider == providerName && e.ContentID == contentID {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return Save(filtered)
+}
+
+// Sync checks every watchlist entry for episodes newer than its
+// last-seen season/episode, using base as the provider host for all
+// entries. Per-entry provider or network failures are logged by the
+// caller's debugf, not returned, so one broken show doesn't block the
+// rest of the watchlist. Entries that gained new episodes are persisted
+// with their updated last-seen markers before returning.
+func Sync(ctx context.Context, base string, debugf func(format string, args ...interface{})) ([]NewEpisode, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	var newEpisodes []NewEpisode
+	changed := false
+
+	for i := range entries {
+		e := &entries[i]
+
+		p, err := provider.Default.New(e.Provider, base)
+		if err != nil {
+			debugf("watchlist: %s: %v", e.Title, err)
+			continue
+		}
+
+		seasons, err := p.GetSeasons(ctx, e.ContentID)
+		if err != nil {
+			debugf("watchlist: %s: getting seasons: %v", e.Title, err)
+			continue
+		}
+
+		for _, s := range seasons {
+			if s.Number < e.LastSeenSeason {
+				continue
+			}
+
+			episodes, err := p.GetEpisodes(ctx, e.ContentID, s.ID)
+			if err != nil {
+				debugf("watchlist: %s: season %d: %v", e.Title, s.Number, err)
+				continue
+			}
+
+			for _, ep := range episodes {
+				if s.Number == e.LastSeenSeason && ep.Number <= e.LastSeenEpisode {
+					continue
+				}
+				if !matchesFilter(e, ep) {
+					continue
+				}
+
+				newEpisodes = append(newEpisodes, NewEpisode{Entry: *e, Season: s.Number, Episode: ep})
+				e.LastSeenSeason = s.Number
+				e.LastSeenEpisode = ep.Number
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		if err := Save(entries); err != nil {
+			return newEpisodes, fmt.Errorf("saving watchlist state: %w", err)
+		}
+	}
+
+	return newEpisodes, nil
+}
+
+// matchesFilter applies an entry's optional title substring filter and
+// max-age-in-days cutoff (based on TMDB-enriched air dates; a no-op if
+// enrichment isn't configured, since ep.AirDate is then always empty).
+func matchesFilter(e *Entry, ep media.Episode) bool {
+	if e.Filter != "" && !strings.Contains(strings.ToLower(ep.Title), strings.ToLower(e.Filter)) {
+		return false
+	}
+	if e.MaxAgeDays > 0 && ep.AirDate != "" {
+		aired, err := time.Parse("2006-01-02", ep.AirDate)
+		if err == nil && time.Since(aired) > time.Duration(e.MaxAgeDays)*24*time.Hour {
+			return false
+		}
+	}
+	return true
+}